@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAliasList   bool
+	flagAliasRemove bool
+)
+
+// aliasCmd represents the alias command
+var aliasCmd = &cobra.Command{
+	Use:   "alias <name> <version>",
+	Short: "Define a name for a Go version",
+	Long: `Define a name that 'gvm use', 'gvm exec' and 'gvm uninstall' accept in
+place of a version, e.g. 'gvm alias prod 1.21.5' and then 'gvm use prod'.
+Unlike 'gvm link' no command is created in ~/.gvm/shims, and the version
+does not have to be installed yet.
+
+The built-in alias latest always refers to the latest stable release.
+A version that aliases point to is not uninstalled unless --force is given,
+which removes those aliases as well.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case flagAliasList:
+			return cobra.NoArgs(cmd, args)
+		case flagAliasRemove:
+			return cobra.ExactArgs(1)(cmd, args)
+		default:
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagAliasList {
+			aliases, err := config.GetAliases()
+			if err != nil {
+				return err
+			}
+			names := make([]string, 0, len(aliases))
+			for name := range aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("%s -> %s\n", name, aliases[name])
+			}
+			fmt.Printf("%s -> latest stable release (built-in)\n", version.AliasLatest)
+			return nil
+		}
+
+		if flagAliasRemove {
+			if err := config.RemoveAlias(args[0]); err != nil {
+				return fmt.Errorf("failed to remove alias %s: %w", args[0], err)
+			}
+			output.PrintSuccess(fmt.Sprintf("Removed alias %s", args[0]))
+			return nil
+		}
+
+		name, versionStr := args[0], args[1]
+		if err := validateAliasName(name); err != nil {
+			return err
+		}
+		// 标准化版本号格式
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+		if _, err := version.ParseSemVer(versionStr); err != nil {
+			return err
+		}
+
+		if err := config.SetAlias(name, versionStr); err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
+		}
+		output.PrintSuccess(fmt.Sprintf("'%s' now refers to Go %s", name, versionStr))
+		if installed, _ := version.New().IsVersionInstalled(versionStr); !installed {
+			output.PrintInfo(fmt.Sprintf("Go %s is not installed yet; run 'gvm install %s'", versionStr, versionStr))
+		}
+		return nil
+	},
+}
+
+// validateAliasName 拒绝会与版本号、list --index 的编号或内置名称混淆的别名
+func validateAliasName(name string) error {
+	switch {
+	case name == "" || strings.ContainsAny(name, " \t/\\"):
+		return fmt.Errorf("invalid alias name %q", name)
+	case name == version.AliasLatest || name == "system":
+		return fmt.Errorf("%s is a built-in name and cannot be redefined", name)
+	}
+	if _, err := version.ParseSemVer(name); err == nil {
+		return fmt.Errorf("alias name %q looks like a version or a 'gvm list --index' number", name)
+	}
+	return nil
+}
+
+// resolveAlias 解析 gvm alias 定义的别名（包括内置的 latest），quiet 为 false 时提示解析结果
+func resolveAlias(vm *version.VersionManager, name string, quiet bool) (string, bool, error) {
+	target, ok, err := vm.ResolveAlias(name)
+	if err != nil || !ok {
+		return "", ok, err
+	}
+	if !quiet {
+		output.PrintInfo(fmt.Sprintf("Alias %s is Go %s", name, target))
+	}
+	return target, true, nil
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.Flags().BoolVar(&flagAliasList, "list", false, "list aliases")
+	aliasCmd.Flags().BoolVar(&flagAliasRemove, "remove", false, "remove the named alias")
+}
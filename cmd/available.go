@@ -1,40 +1,84 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/philokun/gvm/internal/config"
 	"github.com/philokun/gvm/internal/output"
 	"github.com/philokun/gvm/internal/version"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagStable bool
-	flagLimit  int
-	flagJSON   bool
-	flagMirror string
+	flagStable   bool
+	flagLimit    int
+	flagJSON     bool
+	flagMirror   string
+	flagArchived bool
+	flagMinVer   string
+	flagMaxVer   string
+	flagRefresh  bool
+	flagRows     int
+	flagWidth    int
+	flagAll      bool
+	flagPlatform string
+)
+
+// 表格的默认布局：CURRENT 列显示更多行，其他列限制行数；列宽至少为 defaultColWidth
+const (
+	defaultCurrentRows = 15
+	defaultOtherRows   = 20
+	defaultColWidth    = 18
 )
 
 // availableCmd represents the available command
 var availableCmd = &cobra.Command{
 	Use:   "available",
 	Short: "List available Go versions",
-	Long:  "Fetch and list available Go versions from the official source or configured mirror.",
+	Long: `Fetch and list available Go versions from the official source or configured mirror.
+
+The list is cached for an hour in ~/.gvm/cache/versions.json. Use --refresh-cache
+to ignore the cache and fetch a fresh list, e.g. right after a new release;
+--verbose shows whether the list came from the cache and how old it is.
+
+The table shows up to 15 versions in the CURRENT column and 20 in the others;
+use --rows to change that limit for every column or --all to show everything.
+Columns are wide enough for the longest version shown, or --width characters.
+
+--platform keeps only versions that ship an archive for the given os-arch,
+which shows since which release a platform is supported:
+
+  gvm available --platform linux-riscv64`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagRows < 0 || flagWidth < 0 {
+			return fmt.Errorf("--rows and --width must not be negative")
+		}
+		goos, goarch, err := parsePlatform(flagPlatform)
+		if err != nil {
+			return err
+		}
 		if strings.TrimSpace(flagMirror) != "" {
-			os.Setenv("GVM_DL_MIRROR", strings.TrimRight(flagMirror, "/"))
+			config.OverrideSettings(config.Settings{Mirror: flagMirror})
 		}
 		vm := version.New()
-		versions, err := vm.GetAvailableVersions()
+		versions, source, err := vm.LoadAvailableVersions(flagRefresh)
 		if err != nil {
 			return fmt.Errorf("failed to fetch available versions: %w", err)
 		}
+		switch {
+		case source.Stale:
+			output.PrintVerbose("No mirror served the version list; showing the last cached copy")
+		case source.FromCache:
+			output.PrintVerbose(fmt.Sprintf("Using cached version list (fetched %s ago); run with --refresh-cache to update",
+				time.Since(source.FetchedAt).Round(time.Second)))
+		default:
+			output.PrintVerbose("Fetched version list from " + source.Mirror)
+		}
 
 		// filter: if --stable flag is set, only show stable versions; otherwise show all
 		filtered := make([]version.GoVersion, 0, len(versions))
@@ -50,26 +94,61 @@ var availableCmd = &cobra.Command{
 			}
 		}
 
-		// sort by version string descending (newest first)
-		sort.Slice(filtered, func(i, j int) bool { return filtered[i].Version > filtered[j].Version })
+		// --min-version/--max-version: 按语义版本过滤（包含边界）
+		filtered = filterVersionRange(filtered, flagMinVer, flagMaxVer)
+
+		// --archived: 只保留已不再受支持的旧稳定版本（最新两个次版本系列之外）
+		if flagArchived {
+			filtered = archivedVersions(filtered)
+		}
+
+		// --platform: 只保留提供该平台安装包的版本
+		if goos != "" {
+			filtered = filterPlatform(filtered, goos, goarch)
+		}
+
+		// sort by version descending (newest first); go1.9 sorts below go1.21
+		sort.Slice(filtered, func(i, j int) bool { return version.CompareVersions(filtered[i].Version, filtered[j].Version) > 0 })
 		// API 已按最新在前返回；如需限制，截断
 		if flagLimit > 0 && flagLimit < len(filtered) {
 			filtered = filtered[:flagLimit]
 		}
 
+		format, err := outputFormat(output.FormatTable)
+		if err != nil {
+			return err
+		}
+		// --json 等价于 --output json
 		if flagJSON {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(filtered)
+			format = output.FormatJSON
 		}
 
-		// 分类版本
-		current, lts, oldStable, oldUnstable := categorizeVersions(filtered)
+		return output.Render(format, filtered, func() {
+			// 分类版本
+			current, lts, oldStable, oldUnstable := categorizeVersions(filtered)
 
-		// 显示多列表格
-		output.PrintHeader("Available Go versions")
-		printVersionTable(current, lts, oldStable, oldUnstable)
-		return nil
+			// 显示多列表格
+			output.PrintHeader("Available Go versions")
+			layout := tableLayout{currentRows: defaultCurrentRows, otherRows: defaultOtherRows, width: flagWidth}
+			switch {
+			case flagAll:
+				layout.currentRows, layout.otherRows = 0, 0
+			case flagRows > 0:
+				layout.currentRows, layout.otherRows = flagRows, flagRows
+			}
+			printVersionTable(current, lts, oldStable, oldUnstable, layout)
+			if goos != "" {
+				if earliest := earliestVersion(filtered); earliest != "" {
+					output.PrintInfo(fmt.Sprintf("Earliest release shown with a %s-%s archive: %s", goos, goarch, earliest))
+				} else {
+					output.PrintInfo(fmt.Sprintf("No release in the list ships a %s-%s archive", goos, goarch))
+				}
+			}
+		}, func() {
+			for _, v := range filtered {
+				fmt.Println(v.Version)
+			}
+		})
 	},
 }
 
@@ -92,6 +171,44 @@ func parseVersionNumber(version string) (major, minor int, isUnstable bool) {
 	return
 }
 
+// filterVersionRange 返回位于 [min, max] 区间内的版本，空边界表示不限制
+func filterVersionRange(versions []version.GoVersion, min, max string) []version.GoVersion {
+	min, max = strings.TrimSpace(min), strings.TrimSpace(max)
+	if min == "" && max == "" {
+		return versions
+	}
+	result := make([]version.GoVersion, 0, len(versions))
+	for _, v := range versions {
+		if min != "" && version.CompareVersions(v.Version, min) < 0 {
+			continue
+		}
+		if max != "" && version.CompareVersions(v.Version, max) > 0 {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// archivedVersions 返回不属于最新两个次版本系列的稳定版本（Go 官方只维护最新两个系列）
+func archivedVersions(versions []version.GoVersion) []version.GoVersion {
+	maxMinor := 0
+	for _, v := range versions {
+		_, minor, _ := parseVersionNumber(v.Version)
+		if minor > maxMinor {
+			maxMinor = minor
+		}
+	}
+	result := make([]version.GoVersion, 0, len(versions))
+	for _, v := range versions {
+		_, minor, isUnstable := parseVersionNumber(v.Version)
+		if !isUnstable && minor < maxMinor-1 {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 // categorizeVersions 将版本分类为 CURRENT, LTS, OLD STABLE, OLD UNSTABLE
 func categorizeVersions(versions []version.GoVersion) (current, lts, oldStable, oldUnstable []version.GoVersion) {
 	if len(versions) == 0 {
@@ -139,7 +256,7 @@ func categorizeVersions(versions []version.GoVersion) (current, lts, oldStable,
 	// 对每个分类进行排序（降序）
 	sortVersions := func(vs []version.GoVersion) {
 		sort.Slice(vs, func(i, j int) bool {
-			return vs[i].Version > vs[j].Version
+			return version.CompareVersions(vs[i].Version, vs[j].Version) > 0
 		})
 	}
 	sortVersions(current)
@@ -150,23 +267,28 @@ func categorizeVersions(versions []version.GoVersion) (current, lts, oldStable,
 	return
 }
 
+// tableLayout 控制 available 表格的行数与列宽
+type tableLayout struct {
+	currentRows int // CURRENT 列最多显示的行数，0 表示不限制
+	otherRows   int // 其他列最多显示的行数，0 表示不限制
+	width       int // 列宽，0 表示按最长的版本号计算
+}
+
+// truncateVersions 返回 versions 的前 n 项，n 为 0 时不截断
+func truncateVersions(versions []version.GoVersion, n int) []version.GoVersion {
+	if n > 0 && len(versions) > n {
+		return versions[:n]
+	}
+	return versions
+}
+
 // printVersionTable 打印多列表格
-func printVersionTable(current, lts, oldStable, oldUnstable []version.GoVersion) {
+func printVersionTable(current, lts, oldStable, oldUnstable []version.GoVersion, layout tableLayout) {
 	// 限制显示数量（CURRENT 显示更多，其他列限制数量）
-	const maxCurrent = 15
-	const maxOther = 20
-	if len(current) > maxCurrent {
-		current = current[:maxCurrent]
-	}
-	if len(lts) > maxOther {
-		lts = lts[:maxOther]
-	}
-	if len(oldStable) > maxOther {
-		oldStable = oldStable[:maxOther]
-	}
-	if len(oldUnstable) > maxOther {
-		oldUnstable = oldUnstable[:maxOther]
-	}
+	current = truncateVersions(current, layout.currentRows)
+	lts = truncateVersions(lts, layout.otherRows)
+	oldStable = truncateVersions(oldStable, layout.otherRows)
+	oldUnstable = truncateVersions(oldUnstable, layout.otherRows)
 
 	// 计算最大行数
 	maxRows := len(current)
@@ -180,8 +302,18 @@ func printVersionTable(current, lts, oldStable, oldUnstable []version.GoVersion)
 		maxRows = len(oldUnstable)
 	}
 
-	// 定义列宽
-	const colWidth = 18
+	// 定义列宽：未指定时至少为默认宽度，并容纳最长的版本号
+	colWidth := layout.width
+	if colWidth == 0 {
+		colWidth = defaultColWidth
+		for _, column := range [][]version.GoVersion{current, lts, oldStable, oldUnstable} {
+			for _, v := range column {
+				if len(v.Version)+2 > colWidth {
+					colWidth = len(v.Version) + 2
+				}
+			}
+		}
+	}
 
 	// 打印表格顶部边框（使用 ASCII 字符）
 	fmt.Printf("\n+%s+%s+%s+%s+\n",
@@ -251,6 +383,49 @@ func init() {
 	rootCmd.AddCommand(availableCmd)
 	availableCmd.Flags().BoolVar(&flagStable, "stable", false, "show only stable versions")
 	availableCmd.Flags().IntVar(&flagLimit, "limit", 0, "limit the number of results")
-	availableCmd.Flags().BoolVar(&flagJSON, "json", false, "output as JSON")
-	availableCmd.Flags().StringVar(&flagMirror, "mirror", "", "override download mirror base URL")
+	availableCmd.Flags().BoolVar(&flagJSON, "json", false, "output as JSON (same as --output json)")
+	availableCmd.Flags().BoolVar(&flagArchived, "archived", false, "show only archived (no longer supported) releases")
+	availableCmd.Flags().StringVar(&flagMinVer, "min-version", "", "only show versions >= this version (e.g. go1.20)")
+	availableCmd.Flags().StringVar(&flagMaxVer, "max-version", "", "only show versions <= this version")
+	availableCmd.Flags().StringVar(&flagMirror, "mirror", "", "download mirror base URL (GVM_DL_MIRROR takes precedence when set)")
+	availableCmd.Flags().BoolVar(&flagRefresh, "refresh-cache", false, "ignore the cached version list and fetch it again")
+	availableCmd.Flags().IntVar(&flagRows, "rows", 0, "maximum number of versions per table column (default 15 for CURRENT, 20 for the others)")
+	availableCmd.Flags().IntVar(&flagWidth, "width", 0, "table column width (default: fit the longest version)")
+	availableCmd.Flags().StringVar(&flagPlatform, "platform", "", "only show versions with an archive for this os-arch, e.g. linux-riscv64")
+	availableCmd.Flags().BoolVar(&flagAll, "all", false, "show every version in the table instead of the first rows of each column")
+}
+
+// parsePlatform 解析 --platform 的 os-arch（也接受 os/arch），为空时返回空字符串
+func parsePlatform(platform string) (goos, goarch string, err error) {
+	platform = strings.TrimSpace(platform)
+	if platform == "" {
+		return "", "", nil
+	}
+	parts := strings.FieldsFunc(platform, func(r rune) bool { return r == '-' || r == '/' })
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid --platform %q: expected os-arch, e.g. linux-riscv64", platform)
+	}
+	return strings.ToLower(parts[0]), strings.ToLower(parts[1]), nil
+}
+
+// filterPlatform 只保留提供 goos/goarch 压缩包的版本，与 install 选择安装包的规则一致
+func filterPlatform(versions []version.GoVersion, goos, goarch string) []version.GoVersion {
+	out := make([]version.GoVersion, 0, len(versions))
+	for i := range versions {
+		if versions[i].ArchiveFor(goos, goarch) != nil {
+			out = append(out, versions[i])
+		}
+	}
+	return out
+}
+
+// earliestVersion 返回列表中最早的版本，列表为空时返回空字符串
+func earliestVersion(versions []version.GoVersion) string {
+	earliest := ""
+	for _, v := range versions {
+		if earliest == "" || version.CompareVersions(v.Version, earliest) < 0 {
+			earliest = v.Version
+		}
+	}
+	return earliest
 }
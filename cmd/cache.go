@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the download cache",
+	Long: `Downloaded archives are kept in ~/.gvm/cache/downloads so that reinstalling
+a version does not download it again. The least recently used archives are
+evicted when the cache would grow beyond GVM_CACHE_MAX_SIZE (default 2GB,
+accepts values such as 500M or 4G; 0 disables the download cache).`,
+}
+
+// cacheListing 是 gvm cache list 的 JSON 输出
+type cacheListing struct {
+	Archives []version.CachedArchive `json:"archives"`
+	Total    int64                   `json:"total"`
+	Limit    int64                   `json:"limit"`
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached archives and the cache usage",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archives, err := version.ListDownloadCache()
+		if err != nil {
+			return err
+		}
+		listing := cacheListing{Archives: archives, Limit: version.CacheMaxSize()}
+		if listing.Archives == nil {
+			listing.Archives = []version.CachedArchive{}
+		}
+		for _, a := range archives {
+			listing.Total += a.Size
+		}
+
+		format, err := outputFormat(output.FormatTable)
+		if err != nil {
+			return err
+		}
+		return output.Render(format, listing, func() {
+			// 文件名较长，放在最后一列
+			output.PrintTableHeader("Last used", "Size", "Archive")
+			for _, a := range archives {
+				name := a.Name
+				if a.Partial {
+					name += " (partial)"
+				}
+				output.PrintTableRow(a.LastUsed.Format("2006-01-02 15:04"), formatSize(a.Size), name)
+			}
+			fmt.Printf("\nUsing %s of %s\n", formatSize(listing.Total), formatSize(listing.Limit))
+		}, func() {
+			for _, a := range archives {
+				fmt.Printf("%s\t%d\n", a.Name, a.Size)
+			}
+		})
+	},
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove all cached archives and the versions cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		freed, err := version.CleanCache()
+		if err != nil {
+			return err
+		}
+		output.PrintSuccess(fmt.Sprintf("Removed %s from the cache", formatSize(freed)))
+		return nil
+	},
+}
+
+// formatSize 以 MB/GB 形式显示字节数
+func formatSize(n int64) string {
+	if n >= 1<<30 {
+		return fmt.Sprintf("%.2f GB", float64(n)/(1<<30))
+	}
+	return fmt.Sprintf("%.2f MB", float64(n)/(1<<20))
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+}
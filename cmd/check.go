@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// checkResult 是单项检查的结果
+type checkResult struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	Err  string `json:"error,omitempty"`
+}
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify gvm and the active Go version for CI",
+	Long: `Verify that gvm is set up and the active Go version works, for gating CI
+steps:
+
+  gvm check || exit 1
+
+Checks that ~/.gvm is writable, config.json can be read, a version is active
+and passes the integrity check, the go shim points at it and the shims
+directory is on PATH. Prints nothing and exits 0 when everything is healthy;
+otherwise prints the failed checks and exits non-zero. Use --verbose to list
+every check, or --output json for a machine-readable report.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		results := runChecks(version.New())
+
+		failed := 0
+		for _, r := range results {
+			if !r.OK {
+				failed++
+			}
+		}
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		if format == output.FormatJSON {
+			if err := output.Render(format, results, nil, nil); err != nil {
+				return err
+			}
+		} else {
+			for _, r := range results {
+				if !r.OK {
+					fmt.Fprintf(os.Stderr, "%s%s%s %s: %s\n", output.ColorRed, output.SymbolError, output.ColorReset, r.Name, r.Err)
+				} else {
+					output.PrintVerbose(r.Name + ": ok")
+				}
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// runChecks 依次执行各项检查；依赖前一项的检查在前一项失败时跳过
+func runChecks(vm *version.VersionManager) []checkResult {
+	var results []checkResult
+	add := func(name string, err error) bool {
+		r := checkResult{Name: name, OK: err == nil}
+		if err != nil {
+			r.Err = err.Error()
+		}
+		results = append(results, r)
+		return err == nil
+	}
+
+	add("writable", vm.CheckWritable())
+	if !add("config", func() error { _, err := config.Load(); return err }()) {
+		return results
+	}
+
+	current, _ := config.GetCurrentVersion()
+	if !add("active version", func() error {
+		if current == "" {
+			return fmt.Errorf("no version is active; run 'gvm use <version>'")
+		}
+		if installed, _ := vm.IsVersionInstalled(current); !installed {
+			return fmt.Errorf("active version %s is not installed", current)
+		}
+		return nil
+	}()) {
+		return results
+	}
+	add("toolchain", vm.CheckVersion(current))
+	add("shim", func() error {
+		if !vm.IsActive(current) {
+			return fmt.Errorf("the go shim does not point at %s; run 'gvm use %s'", current, current)
+		}
+		return nil
+	}())
+	add("PATH", func() error {
+		shimsDir, err := utils.GetShimsDir()
+		if err != nil {
+			return err
+		}
+		if !utils.PathContains(shimsDir) {
+			return fmt.Errorf("%s is not on PATH", shimsDir)
+		}
+		return nil
+	}())
+	return results
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// completeInstalledVersions 为只接受一个版本参数的命令补全已安装版本；
+// withLinks 为 true 时同时补全命名链接（gvm link 创建的别名）与 gvm alias 定义的别名
+func completeInstalledVersions(withLinks bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		versions, err := version.New().GetInstalledVersions()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		suggestions := append([]string{}, versions...)
+		if withLinks {
+			links, _ := config.GetLinks()
+			names := make([]string, 0, len(links))
+			for name := range links {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				suggestions = append(suggestions, name+"\tlink to "+links[name])
+			}
+			aliases, _ := config.GetAliases()
+			names = names[:0]
+			for name := range aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				suggestions = append(suggestions, name+"\talias for "+aliases[name])
+			}
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
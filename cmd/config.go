@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// configKeys 列出 gvm config 支持的设置项
+var configKeys = []string{"stable-root", "post-use-hook", "post-use-hook-strict", "notify-updates", "install-dir"}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get or set gvm settings",
+	Long: `Get or set gvm settings stored in ~/.gvm/config.json.
+
+Settings:
+  stable-root           keep ~/.gvm/go pointing at the active version, so
+                        that GOROOT=~/.gvm/go follows 'gvm use' (true/false)
+  post-use-hook         shell command run after every successful switch, with
+                        GVM_VERSION, GVM_PREVIOUS_VERSION and GOROOT set and
+                        the new version first on PATH ("" removes it)
+  post-use-hook-strict  fail (and roll back) the switch when the hook fails
+                        instead of only warning (true/false)
+  notify-updates        check GitHub for a newer gvm at most once a day and
+                        print a one-line hint after a command (true/false,
+                        off by default; nothing is sent besides the request)
+  install-dir           directory Go versions are installed in (default
+                        ~/.gvm/versions); setting it moves the installed
+                        versions there. GVM_HOME overrides it when set
+
+The hook runs with your privileges whenever the active version changes,
+including 'gvm install' activation and 'gvm import'. Only configure commands
+you trust, and keep ~/.gvm/config.json writable only by you; use
+'gvm use --no-hook' to switch without running it.
+
+Per-version go settings are stored in that version's $GOROOT/go.env, which
+Go 1.21 and later read as defaults below 'go env -w' and the environment:
+  gvm config set-goenv go1.22.1 GOTOOLCHAIN=local GOFLAGS=-mod=mod
+  gvm config get-goenv go1.22.1 [KEY]`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "stable-root":
+			enabled, err := config.GetStableRoot()
+			if err != nil {
+				return err
+			}
+			fmt.Println(enabled)
+			return nil
+		case "post-use-hook", "post-use-hook-strict":
+			hook, strict, err := config.GetPostUseHook()
+			if err != nil {
+				return err
+			}
+			if args[0] == "post-use-hook" {
+				fmt.Println(hook)
+			} else {
+				fmt.Println(strict)
+			}
+			return nil
+		case "notify-updates":
+			enabled, err := config.GetNotifyUpdates()
+			if err != nil {
+				return err
+			}
+			fmt.Println(enabled)
+			return nil
+		case "install-dir":
+			// 输出实际生效的目录（GVM_HOME 优先）
+			fmt.Println(config.ResolveInstallDir())
+			return nil
+		}
+		return unknownConfigKey(args[0])
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "stable-root":
+			enabled, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid value %q for stable-root: expected true or false", args[1])
+			}
+			if err := config.SetStableRoot(enabled); err != nil {
+				return err
+			}
+			// 立即创建或删除 ~/.gvm/go，而不是等到下一次 use
+			if err := version.New().SyncStableRoot(); err != nil {
+				return fmt.Errorf("failed to update stable root: %w", err)
+			}
+			if enabled {
+				output.PrintSuccess("stable-root enabled; set GOROOT=~/.gvm/go to follow the active version")
+			} else {
+				output.PrintSuccess("stable-root disabled")
+			}
+			return nil
+		case "post-use-hook":
+			command := strings.TrimSpace(args[1])
+			if err := config.SetPostUseHook(command); err != nil {
+				return err
+			}
+			if command == "" {
+				output.PrintSuccess("post-use hook removed")
+			} else {
+				output.PrintSuccess(fmt.Sprintf("post-use hook set; it runs after every version switch: %s", command))
+			}
+			return nil
+		case "post-use-hook-strict":
+			strict, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid value %q for post-use-hook-strict: expected true or false", args[1])
+			}
+			if err := config.SetPostUseHookStrict(strict); err != nil {
+				return err
+			}
+			output.PrintSuccess(fmt.Sprintf("post-use-hook-strict set to %t", strict))
+			return nil
+		case "notify-updates":
+			enabled, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid value %q for notify-updates: expected true or false", args[1])
+			}
+			if err := config.SetNotifyUpdates(enabled); err != nil {
+				return err
+			}
+			output.PrintSuccess(fmt.Sprintf("notify-updates set to %t", enabled))
+			if enabled && version.GVMVersion() == "" {
+				output.PrintWarning("this gvm binary has no version information (built with 'go build'), so no update checks will run")
+			}
+			return nil
+		case "install-dir":
+			return setInstallDir(args[1])
+		}
+		return unknownConfigKey(args[0])
+	},
+}
+
+var configGetGoEnvCmd = &cobra.Command{
+	Use:               "get-goenv <version> [key]",
+	Short:             "Print the go.env settings of an installed version",
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeInstalledVersions(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionStr := normalizeVersion(args[0])
+		entries, err := version.New().GetGoEnv(versionStr)
+		if err != nil {
+			return err
+		}
+		if len(args) == 2 {
+			for _, e := range entries {
+				if e.Key == args[1] {
+					fmt.Println(e.Value)
+					return nil
+				}
+			}
+			return fmt.Errorf("%s is not set in the go.env of Go %s", args[1], versionStr)
+		}
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		return output.Render(format, entries, nil, func() {
+			for _, e := range entries {
+				fmt.Printf("%s=%s\n", e.Key, e.Value)
+			}
+		})
+	},
+}
+
+var configSetGoEnvCmd = &cobra.Command{
+	Use:   "set-goenv <version> KEY=VALUE...",
+	Short: "Write settings into the go.env of an installed version",
+	Long: `Write settings into $GOROOT/go.env of an installed version, creating the
+file if needed. An existing setting with the same name is replaced; an empty
+value (KEY=) removes it. Names are checked against 'go env' of that version.`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeInstalledVersions(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionStr := normalizeVersion(args[0])
+		vm := version.New()
+		for _, kv := range args[1:] {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				return fmt.Errorf("invalid setting %q: expected KEY=VALUE", kv)
+			}
+			if err := vm.SetGoEnv(versionStr, key, value); err != nil {
+				return err
+			}
+		}
+		output.PrintSuccess(fmt.Sprintf("Updated go.env of Go %s", versionStr))
+		if version.CompareVersions(versionStr, version.GoEnvMinVersion) < 0 {
+			output.PrintWarning(fmt.Sprintf("Go %s does not read $GOROOT/go.env; the settings take effect from %s", versionStr, version.GoEnvMinVersion))
+		}
+		return nil
+	},
+}
+
+// setInstallDir 将已安装的版本移动到 dir 并写入配置
+func setInstallDir(dir string) error {
+	if strings.TrimSpace(dir) == "" {
+		return fmt.Errorf("install-dir cannot be empty")
+	}
+	current, err := config.GetInstallDir()
+	if err != nil {
+		return err
+	}
+	// 从配置中的目录移动，而不是 GVM_HOME 指定的目录
+	vm := version.NewWithSettings(current, config.ResolveSettings())
+	if err := vm.CheckWritable(); err != nil {
+		return err
+	}
+	moved, err := vm.MoveInstallDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, v := range moved {
+		output.PrintInfo(fmt.Sprintf("Moved %s", v))
+	}
+	output.PrintSuccess(fmt.Sprintf("install-dir set to %s", vm.GetInstallDir()))
+	if env := strings.TrimSpace(os.Getenv("GVM_HOME")); env != "" {
+		output.PrintWarning(fmt.Sprintf("GVM_HOME is set to %s and takes precedence over install-dir; unset it to use the new directory", env))
+	}
+	return nil
+}
+
+// normalizeVersion 为版本号补上 go 前缀
+func normalizeVersion(v string) string {
+	if !strings.HasPrefix(v, "go") {
+		return "go" + v
+	}
+	return v
+}
+
+func unknownConfigKey(key string) error {
+	return fmt.Errorf("unknown setting %q (supported: %v)", key, configKeys)
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetGoEnvCmd)
+	configCmd.AddCommand(configSetGoEnvCmd)
+}
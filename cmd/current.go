@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCurrentCheck   bool
+	flagCurrentResolve bool
+)
+
+// currentCmd represents the current command
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the active Go version",
+	Long: `Print the Go version that is currently active.
+
+With --check, also verify that the active version's installation is intact
+and its go binary still runs.
+
+With --resolve, print how the active version was determined: the version
+selected in config.json, the go shim, the go found on PATH and any GOROOT
+override, ending with the effective version and GOROOT.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm := version.New()
+		if flagCurrentResolve {
+			return printResolution(vm)
+		}
+		current, err := vm.GetCurrentVersion()
+		if err != nil {
+			return fmt.Errorf("failed to get current version: %w", err)
+		}
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		if err := output.Render(format, currentEntry{Version: current}, func() {
+			output.PrintTableHeader("Current")
+			output.PrintTableRow(current)
+		}, func() {
+			fmt.Println(current)
+		}); err != nil {
+			return err
+		}
+
+		if !flagCurrentCheck || current == "system" {
+			return nil
+		}
+		return checkActiveVersion(vm, current)
+	},
+}
+
+// currentEntry 是 current 命令的 JSON 输出结构
+type currentEntry struct {
+	Version string `json:"version"`
+}
+
+// printResolution 打印当前版本的解析链
+func printResolution(vm *version.VersionManager) error {
+	res := vm.Resolve()
+	format, err := outputFormat(output.FormatPlain)
+	if err != nil {
+		return err
+	}
+	return output.Render(format, res, nil, func() {
+		for i, step := range res.Steps {
+			prefix := "  "
+			if i > 0 {
+				prefix = output.SymbolArrow + " "
+			}
+			fmt.Printf("%s%-7s %s\n", prefix, step.Source+":", step.Detail)
+		}
+		if res.Version == "" {
+			fmt.Println("\nNo Go is active")
+			return
+		}
+		fmt.Printf("\nEffective version: %s\nGOROOT: %s\n", res.Version, res.GOROOT)
+	})
+}
+
+// checkActiveVersion 校验当前版本是否可用，损坏时提示同系列的其他已安装版本
+func checkActiveVersion(vm *version.VersionManager, current string) error {
+	checkErr := vm.CheckVersion(current)
+	if checkErr == nil {
+		output.PrintSuccess(fmt.Sprintf("Go %s is healthy", current))
+		return nil
+	}
+
+	output.PrintWarning(fmt.Sprintf("Go %s appears to be broken: %s", current, checkErr.Error()))
+
+	installed, err := vm.GetInstalledVersions()
+	if err == nil {
+		series := version.Series(current)
+		for _, v := range installed {
+			if v == current || version.Series(v) != series {
+				continue
+			}
+			if vm.CheckVersion(v) == nil {
+				output.PrintInfo(fmt.Sprintf("Go %s from the same series is installed; run 'gvm use %s' to switch", v, v))
+				break
+			}
+		}
+	}
+	return fmt.Errorf("active version %s failed the integrity check", current)
+}
+
+func init() {
+	rootCmd.AddCommand(currentCmd)
+	currentCmd.Flags().BoolVar(&flagCurrentCheck, "check", false, "verify the active version's installation still works")
+	currentCmd.Flags().BoolVar(&flagCurrentResolve, "resolve", false, "explain how the active version was determined")
+}
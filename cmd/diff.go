@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <version1> <version2>",
+	Short: "Compare the tool inventories of two installed Go versions",
+	Long: `Compare two installed Go versions: the reported version, the tools in bin/
+and the settings in go.env. This command is read-only.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v1, v2 := args[0], args[1]
+		// 标准化版本号格式
+		if !strings.HasPrefix(v1, "go") {
+			v1 = "go" + v1
+		}
+		if !strings.HasPrefix(v2, "go") {
+			v2 = "go" + v2
+		}
+
+		vm := version.New()
+		tools1, err := vm.ListTools(v1)
+		if err != nil {
+			return err
+		}
+		tools2, err := vm.ListTools(v2)
+		if err != nil {
+			return err
+		}
+
+		result := diffResult{
+			From:        v1,
+			To:          v2,
+			FromVersion: readVersionLine(vm.GetVersionDir(v1)),
+			ToVersion:   readVersionLine(vm.GetVersionDir(v2)),
+		}
+		result.AddedTools, result.RemovedTools = diffStrings(tools1, tools2)
+		result.AddedEnv, result.RemovedEnv = diffStrings(
+			readGoEnv(vm.GetVersionDir(v1)),
+			readGoEnv(vm.GetVersionDir(v2)))
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		return output.Render(format, result, nil, func() {
+			printDiff(result)
+		})
+	},
+}
+
+// diffResult 是 diff 命令的输出结构
+type diffResult struct {
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	FromVersion  string   `json:"from_version"`
+	ToVersion    string   `json:"to_version"`
+	AddedTools   []string `json:"added_tools"`
+	RemovedTools []string `json:"removed_tools"`
+	AddedEnv     []string `json:"added_env"`
+	RemovedEnv   []string `json:"removed_env"`
+}
+
+func printDiff(r diffResult) {
+	fmt.Printf("--- %s (%s)\n", r.From, r.FromVersion)
+	fmt.Printf("+++ %s (%s)\n", r.To, r.ToVersion)
+
+	if len(r.AddedTools) == 0 && len(r.RemovedTools) == 0 {
+		fmt.Println("\nbin/: no changes")
+	} else {
+		fmt.Println("\nbin/:")
+		for _, t := range r.RemovedTools {
+			fmt.Printf("%s- %s%s\n", output.ColorRed, t, output.ColorReset)
+		}
+		for _, t := range r.AddedTools {
+			fmt.Printf("%s+ %s%s\n", output.ColorGreen, t, output.ColorReset)
+		}
+	}
+
+	if len(r.AddedEnv) > 0 || len(r.RemovedEnv) > 0 {
+		fmt.Println("\ngo.env:")
+		for _, l := range r.RemovedEnv {
+			fmt.Printf("%s- %s%s\n", output.ColorRed, l, output.ColorReset)
+		}
+		for _, l := range r.AddedEnv {
+			fmt.Printf("%s+ %s%s\n", output.ColorGreen, l, output.ColorReset)
+		}
+	}
+}
+
+// diffStrings 返回 b 中新增的与 a 中被移除的元素
+func diffStrings(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, s := range a {
+		inA[s] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+		if !inA[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			removed = append(removed, s)
+		}
+	}
+	return
+}
+
+// readVersionLine 读取安装目录中 VERSION 文件的第一行
+func readVersionLine(dir string) string {
+	b, err := os.ReadFile(filepath.Join(dir, "VERSION"))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+}
+
+// readGoEnv 读取安装目录中 go.env 的有效配置行（忽略注释与空行）
+func readGoEnv(dir string) []string {
+	b, err := os.ReadFile(filepath.Join(dir, "go.env"))
+	if err != nil {
+		return nil
+	}
+	lines := []string{}
+	for _, ln := range strings.Split(string(b), "\n") {
+		ln = strings.TrimSpace(ln)
+		if ln == "" || strings.HasPrefix(ln, "#") {
+			continue
+		}
+		lines = append(lines, ln)
+	}
+	return lines
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
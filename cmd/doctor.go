@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose why go does not run the expected version",
+	Long: `Check the setup that decides which go runs and print every finding:
+
+  - the shims directory is on PATH, and no other go comes before it
+  - the go shim points at current_version from config.json
+  - each installed version has an intact bin/go and VERSION file
+  - GOROOT is not set to a different Go installation
+
+Critical problems (go would run the wrong version or not at all) are printed
+as errors and make gvm exit non-zero; problems that do not affect the active
+version are printed as warnings. Unlike 'gvm check', which is silent when
+everything is healthy, every check is reported.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		vm := version.New()
+		current, _ := config.GetCurrentVersion()
+
+		critical := 0
+		for _, check := range []func(*version.VersionManager, string) int{
+			doctorPath, doctorShim, doctorInstalls, doctorGOROOT,
+		} {
+			critical += check(vm, current)
+		}
+		if critical > 0 {
+			return fmt.Errorf("%d critical problem(s) found", critical)
+		}
+		output.PrintSuccess("No critical problems found")
+		return nil
+	},
+}
+
+// doctorPath 检查 shims 目录是否在 PATH 中，以及它之前是否有其他 go；返回严重问题数
+func doctorPath(vm *version.VersionManager, current string) int {
+	shimsDir, err := utils.GetShimsDir()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Cannot locate the shims directory: %s", err.Error()))
+		return 1
+	}
+	shimsAt := -1
+	var before, after []string
+	for i, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		if filepath.Clean(dir) == filepath.Clean(shimsDir) {
+			if shimsAt < 0 {
+				shimsAt = i + 1
+			}
+			continue
+		}
+		if !utils.FileExists(filepath.Join(dir, goExecutable())) {
+			continue
+		}
+		entry := fmt.Sprintf("%s (PATH entry %d)", dir, i+1)
+		if shimsAt < 0 {
+			before = append(before, entry)
+		} else {
+			after = append(after, entry)
+		}
+	}
+
+	if shimsAt < 0 {
+		output.PrintError(fmt.Sprintf("%s is not on PATH; %s", shimsDir, utils.ActivationHint()))
+		return 1
+	}
+	problems := 0
+	if len(before) > 0 {
+		output.PrintError(fmt.Sprintf("Another go comes before the gvm shims (PATH entry %d): %s", shimsAt, strings.Join(before, ", ")))
+		problems++
+	} else {
+		output.PrintSuccess(fmt.Sprintf("%s is on PATH (entry %d) before any other go", shimsDir, shimsAt))
+	}
+	if len(after) > 0 {
+		output.PrintInfo(fmt.Sprintf("Other go installs later on PATH (not used): %s", strings.Join(after, ", ")))
+	}
+	return problems
+}
+
+// doctorShim 检查 go shim 是否指向 config.json 中的当前版本；返回严重问题数
+func doctorShim(vm *version.VersionManager, current string) int {
+	if current == "" {
+		output.PrintWarning("No version is selected; run 'gvm use <version>'")
+		return 0
+	}
+	target, err := utils.ShimTarget("go")
+	if err != nil {
+		output.PrintError(fmt.Sprintf("The go shim is missing; run 'gvm use %s'", current))
+		return 1
+	}
+	if !vm.IsActive(current) {
+		output.PrintError(fmt.Sprintf("config.json selects %s but the go shim points to %s; run 'gvm use %s'", current, target, current))
+		return 1
+	}
+	output.PrintSuccess(fmt.Sprintf("The go shim points at the current version %s", current))
+	return 0
+}
+
+// doctorInstalls 检查每个已安装版本的 bin/go 与 VERSION；只有当前版本损坏才算严重问题
+func doctorInstalls(vm *version.VersionManager, current string) int {
+	versions, err := vm.GetInstalledVersions()
+	if err != nil {
+		output.PrintError(fmt.Sprintf("Cannot list installed versions: %s", err.Error()))
+		return 1
+	}
+	if len(versions) == 0 {
+		output.PrintWarning("No Go versions are installed; run 'gvm install latest'")
+		return 0
+	}
+	problems := 0
+	for _, v := range versions {
+		err := vm.CheckVersion(v)
+		switch {
+		case err == nil:
+			output.PrintSuccess(fmt.Sprintf("%s is intact", v))
+		case v == current:
+			output.PrintError(fmt.Sprintf("%s (current) is broken: %s; run 'gvm repair %s'", v, err.Error(), v))
+			problems++
+		default:
+			output.PrintWarning(fmt.Sprintf("%s is broken: %s; run 'gvm repair %s'", v, err.Error(), v))
+		}
+	}
+	return problems
+}
+
+// doctorGOROOT 检查 GOROOT 环境变量是否指向当前版本之外的 Go；返回严重问题数
+func doctorGOROOT(vm *version.VersionManager, current string) int {
+	goroot := strings.TrimSpace(os.Getenv("GOROOT"))
+	if goroot == "" {
+		output.PrintSuccess("GOROOT is not set (each version finds its own root)")
+		return 0
+	}
+	if current != "" && utils.PathWithin(goroot, vm.GetVersionDir(current)) {
+		output.PrintWarning(fmt.Sprintf("GOROOT is set to %s; it matches %s now but will not follow 'gvm use'", goroot, current))
+		return 0
+	}
+	if v, ok := vm.ManagedVersion(goroot); ok {
+		output.PrintError(fmt.Sprintf("GOROOT points at %s, not the current version; unset GOROOT", v))
+	} else {
+		output.PrintError(fmt.Sprintf("GOROOT points at %s, outside gvm; unset GOROOT", goroot))
+	}
+	return 1
+}
+
+// goExecutable 返回当前平台上 go 可执行文件的文件名
+func goExecutable() string {
+	if runtime.GOOS == "windows" {
+		return "go.exe"
+	}
+	return "go"
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
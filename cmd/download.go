@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDownloadAll  bool
+	flagDownloadDir  string
+	flagDownloadJobs int
+)
+
+// downloadCmd represents the download command
+var downloadCmd = &cobra.Command{
+	Use:   "download <version>",
+	Short: "Download release archives without installing them",
+	Long: `Download the archive of a Go version for the current platform, or with
+--all-platforms every archive of the release, verifying each SHA256. The
+output directory also receives a SHA256SUMS file and a version.json in the
+format of go.dev/dl/?mode=json, which makes it suitable for seeding an
+offline mirror.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionStr := args[0]
+		// 标准化版本号格式
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+
+		vm := version.New()
+		output.PrintProgress(fmt.Sprintf("Downloading Go %s to %s...", versionStr, flagDownloadDir))
+		results, err := vm.DownloadVersion(versionStr, flagDownloadDir, flagDownloadAll, flagDownloadJobs)
+		if results == nil && err != nil {
+			return err
+		}
+
+		failed := 0
+		output.PrintHeader("Download summary")
+		// 文件名较长，放在最后一列
+		output.PrintTableHeader("Result", "Size", "Archive")
+		for _, r := range results {
+			result := "ok"
+			if r.Err != nil {
+				result = "failed"
+				failed++
+			}
+			output.PrintTableRow(result, formatSize(int64(r.Size)), r.Filename)
+		}
+		fmt.Printf("\n%d succeeded, %d failed\n", len(results)-failed, failed)
+
+		for _, r := range results {
+			if r.Err != nil {
+				output.PrintError(fmt.Sprintf("%s: %s", r.Filename, r.Err.Error()))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d downloads failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+	downloadCmd.Flags().BoolVar(&flagDownloadAll, "all-platforms", false, "download the archives of every platform")
+	downloadCmd.Flags().StringVarP(&flagDownloadDir, "dir", "d", ".", "directory to write the archives to")
+	downloadCmd.Flags().IntVarP(&flagDownloadJobs, "jobs", "j", 4, "number of archives to download in parallel")
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// example 是一条可直接运行的示例命令
+type example struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// examplesEntry 是 examples 命令的 JSON 输出结构
+type examplesEntry struct {
+	Name     string    `json:"name"`
+	Examples []example `json:"examples"`
+}
+
+// examplesCmd represents the examples command
+var examplesCmd = &cobra.Command{
+	Use:   "examples [command]",
+	Short: "Print runnable example invocations for this platform",
+	Long: `Print example invocations of every command, or of one command. The lines that
+reload PATH are tailored to the detected platform and shell, e.g.
+'source ~/.zshrc' for zsh or the env.ps1 line for PowerShell, so they can be
+copied as they are.
+
+The same examples appear in the Examples section of each command's --help.`,
+	Args: cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return exampleCommands(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+
+		names := exampleCommands()
+		if len(args) == 1 {
+			if len(commandExamples(args[0])) == 0 {
+				return fmt.Errorf("no examples for %q; available: %s", args[0], strings.Join(names, ", "))
+			}
+			names = []string{args[0]}
+		}
+		entries := make([]examplesEntry, 0, len(names))
+		for _, name := range names {
+			entries = append(entries, examplesEntry{Name: name, Examples: commandExamples(name)})
+		}
+
+		return output.Render(format, entries, nil, func() {
+			if len(args) == 0 {
+				fmt.Printf("# Detected platform: %s/%s, shell: %s\n\n", runtime.GOOS, runtime.GOARCH, detectedShell())
+			}
+			for i, e := range entries {
+				if i > 0 {
+					fmt.Println()
+				}
+				if len(args) == 0 {
+					fmt.Printf("%s:\n", e.Name)
+				}
+				fmt.Println(formatExamples(e.Examples))
+			}
+		})
+	},
+}
+
+// detectedShell 返回当前 shell 的名称，见 utils.DetectShell
+func detectedShell() string {
+	if shell, _ := utils.DetectShell(); shell != "" {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	return "unknown"
+}
+
+// activationExamples 返回让当前终端立即使用新 PATH 的示例，无法识别 shell 时为空
+func activationExamples() []example {
+	line := utils.ActivationCommand()
+	if line == "" {
+		return nil
+	}
+	return []example{{line, "reload PATH in this terminal (" + detectedShell() + ")"}}
+}
+
+// commandExamples 返回命令的示例；涉及 PATH 的命令会附上当前 shell 对应的生效命令
+func commandExamples(name string) []example {
+	switch name {
+	case "setup":
+		return append([]example{{"gvm setup", "create the shims directory and add it to PATH"}}, activationExamples()...)
+	case "install":
+		return append([]example{
+			{"gvm install 1.22.3", "install Go 1.22.3 (the go prefix is optional)"},
+			{"gvm install --from-file .go-version", "install the version named in .go-version"},
+			{"gvm install", "install the toolchain (or go) version from ./go.mod"},
+			{"gvm install 1.22.3 --no-src", "install without the standard library sources"},
+			{"gvm install 1.4.3 --archived --checksum <sha256>", "install a release missing from the versions list"},
+			{"gvm install 1.22.3 --retry-mirror-on-checksum", "fall back to the next mirror when one serves a bad archive"},
+		}, activationExamples()...)
+	case "use":
+		return append([]example{
+			{"gvm use 1.22.3", "switch to Go 1.22.3"},
+			{"gvm use --from-gomod go.mod", "switch to the version declared in go.mod"},
+			{"gvm use", "switch to the version in .go-version here or in a parent directory"},
+			{"gvm use 2", "switch to entry 2 of 'gvm list --index'"},
+			{"gvm use 1.22.3 --json", "switch and print the result as JSON"},
+		}, activationExamples()...)
+	case "shell":
+		return []example{{"gvm shell 1.21.5", "try Go 1.21.5 in a subshell; 'exit' restores the active version"}}
+	case "exec":
+		return []example{
+			{"gvm exec 1.20 -- go build ./...", "build with Go 1.20 without changing the active version"},
+			{"gvm exec 1.21.5 -- go test -race ./...", "the command's exit code becomes gvm's exit code"},
+		}
+	case "list":
+		return []example{
+			{"gvm list", "list installed versions, the active one marked with *"},
+			{"gvm list --tree", "group installed versions by minor series"},
+			{"gvm list --index", "number the versions for 'gvm use N' and 'gvm uninstall N'"},
+		}
+	case "available":
+		return []example{
+			{"gvm available --stable", "list stable releases"},
+			{"gvm available --min-version go1.21 --json", "list releases since Go 1.21 as JSON"},
+			{"gvm available --platform linux-riscv64", "list releases that ship a linux-riscv64 archive"},
+		}
+	case "current":
+		return []example{
+			{"gvm current", "print the active version"},
+			{"gvm current --check", "verify that the active version still works"},
+		}
+	case "which":
+		return []example{
+			{"gvm which", "print the active go binary"},
+			{"gvm which --all --json", "map every installed version to its go binary"},
+		}
+	case "uninstall":
+		return []example{
+			{"gvm uninstall 1.21.5 --dry-run", "show what would be removed"},
+			{"gvm uninstall 1.21.5", "remove Go 1.21.5"},
+		}
+	case "repair":
+		return []example{
+			{"gvm repair 1.22.3", "re-extract a broken install, from the download cache when possible"},
+			{"gvm repair 1.22.3 --force", "re-extract even if the install looks intact"},
+		}
+	case "prune":
+		return []example{{"gvm prune --dry-run", "show old patch versions that would be removed"}}
+	case "history":
+		return []example{{"gvm history -n 10", "show the last 10 switches"}}
+	case "semver":
+		return []example{
+			{"gvm semver 1.22rc1", "parse a version"},
+			{"gvm semver compare go1.21.5 go1.22.0", "print -1, 0 or 1"},
+		}
+	case "check":
+		return []example{{"gvm check", "exit non-zero in CI when gvm or the active version is broken"}}
+	case "doctor":
+		return []example{{"gvm doctor", "report PATH order, shim target, broken installs and GOROOT conflicts"}}
+	case "test-install":
+		return []example{{"gvm test-install 1.22.3", "build a test program with Go 1.22.3"}}
+	case "diff":
+		return []example{{"gvm diff go1.21.6 go1.22.0", "compare the tools and go.env of two versions"}}
+	case "link":
+		return []example{
+			{"gvm link go1.20.14 go1.20", "run Go 1.20 as 'go1.20'"},
+			{"gvm link --list", "list named links"},
+			{"gvm link --remove go1.20", "remove a named link"},
+		}
+	case "alias":
+		return []example{
+			{"gvm alias prod 1.21.5", "make 'gvm use prod' switch to Go 1.21.5"},
+			{"gvm alias --list", "list aliases, including the built-in latest"},
+			{"gvm alias --remove prod", "remove an alias"},
+		}
+	case "download":
+		return []example{{"gvm download go1.22.0 --all-platforms --dir ./mirror/dl -j 8", "download every archive of a release for a mirror"}}
+	case "cache":
+		return []example{
+			{"gvm cache list", "show the cached downloads"},
+			{"gvm cache clean", "remove the cached downloads"},
+		}
+	case "export":
+		return []example{
+			{"gvm export > toolchains.json", "write the manifest to stdout"},
+			{"gvm export --file toolchains.json --include-mirror", "also record the download mirror"},
+		}
+	case "import":
+		return []example{{"gvm import toolchains.json", "install the versions of a manifest"}}
+	case "config":
+		return []example{
+			{"gvm config set stable-root true", "keep ~/.gvm/go pointing at the active version"},
+			{"gvm config get stable-root", "print a setting"},
+			{`gvm config set post-use-hook "make tools"`, "run a command after every switch"},
+			{"gvm config set install-dir /data/gvm/versions", "move the installed versions to a larger disk"},
+		}
+	}
+	return nil
+}
+
+// exampleCommands 返回有示例的命令名，按字母排序
+func exampleCommands() []string {
+	var names []string
+	for _, c := range rootCmd.Commands() {
+		if len(commandExamples(c.Name())) > 0 {
+			names = append(names, c.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatExamples 将示例排版为 --help 中 Examples 一节的格式，注释按列对齐
+func formatExamples(examples []example) string {
+	width := 0
+	for _, e := range examples {
+		if len(e.Command) > width {
+			width = len(e.Command)
+		}
+	}
+	lines := make([]string, len(examples))
+	for i, e := range examples {
+		lines[i] = fmt.Sprintf("  %-*s  # %s", width, e.Command, e.Description)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setExamples 为每个有示例的命令填充 --help 中的 Examples。示例中的 shell 取决于 --shell，
+// 因此在显示帮助时（标志已解析、所有命令都已注册）才生成
+func setExamples() {
+	for _, c := range rootCmd.Commands() {
+		if examples := commandExamples(c.Name()); len(examples) > 0 {
+			c.Example = formatExamples(examples)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(examplesCmd)
+
+	defaultHelp := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		utils.SetShellOverride(flagShell)
+		setExamples()
+		defaultHelp(c, args)
+	})
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec <version> -- <command> [args...]",
+	Short: "Run a command with a Go version active",
+	Long: `Run a single command in an environment where the given version's GOROOT
+and bin directory come first on PATH. The active version and the shell
+configuration are not changed.
+
+stdin, stdout and stderr are passed through and gvm exits with the command's
+exit code. Everything after the version is passed to the command unchanged;
+the '--' separator is optional but keeps the command's flags away from gvm.`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeInstalledVersions(true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionStr, command := args[0], args[1:]
+		if command[0] == "--" {
+			command = command[1:]
+			if len(command) == 0 {
+				return fmt.Errorf("no command given after '--'")
+			}
+		}
+		cmd.SilenceUsage = true
+		vm := version.New()
+
+		// 命名链接与 gvm alias 定义的别名可以代替版本号
+		if target, ok := vm.ResolveLink(versionStr); ok {
+			versionStr = target
+		} else if target, ok, err := resolveAlias(vm, versionStr, true); err != nil {
+			return err
+		} else if ok {
+			versionStr = target
+		}
+
+		// 标准化版本号格式
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+
+		installed, err := vm.IsVersionInstalled(versionStr)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			return fmt.Errorf("version %s is not installed; run 'gvm install %s' first", versionStr, versionStr)
+		}
+		binPath, err := vm.GetBinPath(versionStr)
+		if err != nil {
+			return err
+		}
+
+		child := exec.Command(lookPathIn(command[0], binPath), command[1:]...)
+		child.Env = versionEnv(os.Environ(), binPath)
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+
+		if err := child.Run(); err != nil {
+			// 命令的退出码原样返回，不视为 gvm 的错误
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			return fmt.Errorf("failed to run %s: %w", command[0], err)
+		}
+		return nil
+	},
+}
+
+// lookPathIn 优先在 binPath 中查找命令：exec.Command 按 gvm 自身的 PATH 查找，
+// 而子进程的 PATH 以 binPath 开头，不在其中的命令仍交给 exec.Command 查找
+func lookPathIn(name, binPath string) string {
+	if strings.ContainsAny(name, `/\`) {
+		return name
+	}
+	candidate := filepath.Join(binPath, name)
+	if runtime.GOOS == "windows" && filepath.Ext(name) == "" {
+		candidate += ".exe"
+	}
+	if utils.FileExists(candidate) {
+		return candidate
+	}
+	return name
+}
+
+func init() {
+	// 版本之后的参数全部属于要运行的命令
+	execCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(execCmd)
+}
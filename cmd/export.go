@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// manifest 描述一台机器上 gvm 管理的工具链集合，由 export 生成、import 还原
+type manifest struct {
+	Versions []string          `json:"versions"`
+	Current  string            `json:"current,omitempty"`
+	Links    map[string]string `json:"links,omitempty"`
+	Mirror   string            `json:"mirror,omitempty"`
+}
+
+var (
+	flagExportFile   string
+	flagExportMirror bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the installed versions to a manifest",
+	Long: `Write the installed Go versions, the active version and the named links
+to a JSON manifest that 'gvm import' can restore on another machine.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm := version.New()
+		versions, err := vm.GetInstalledVersions()
+		if err != nil {
+			return fmt.Errorf("failed to get installed versions: %w", err)
+		}
+
+		m := manifest{Versions: versions}
+		if current, err := config.GetCurrentVersion(); err == nil {
+			if installed, _ := vm.IsVersionInstalled(current); current != "" && installed {
+				m.Current = current
+			}
+		}
+		if links, err := config.GetLinks(); err == nil && len(links) > 0 {
+			m.Links = links
+		}
+		if mirror := config.ResolveSettings().Mirror; flagExportMirror && mirror != config.DefaultMirror {
+			m.Mirror = mirror
+		}
+
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		data = append(data, '\n')
+
+		if flagExportFile == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(flagExportFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+		output.PrintSuccess(fmt.Sprintf("Exported %d versions to %s", len(m.Versions), flagExportFile))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&flagExportFile, "file", "f", "", "write the manifest to a file instead of stdout")
+	exportCmd.Flags().BoolVar(&flagExportMirror, "include-mirror", false, "record the download mirror (GVM_DL_MIRROR) in the manifest")
+}
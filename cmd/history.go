@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var flagHistoryLimit int
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show when and why the active Go version changed",
+	Long: `Print the version switches recorded in ~/.gvm/history.log, oldest first.
+
+Every successful switch is recorded with its source:
+  manual   gvm use <version>
+  dotfile  gvm use --from-gomod <file> or --from-file <file>
+  alias    gvm use <name> for a name created with 'gvm link'
+  install  activation after 'gvm install'
+  import   the active version restored by 'gvm import'
+
+Use -n to show only the most recent entries.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := version.ReadHistory()
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+		if flagHistoryLimit > 0 && len(entries) > flagHistoryLimit {
+			entries = entries[len(entries)-flagHistoryLimit:]
+		}
+
+		format, err := outputFormat(output.FormatTable)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 && format != output.FormatJSON {
+			output.PrintInfo("No version switches recorded yet")
+			return nil
+		}
+
+		return output.Render(format, entries, func() {
+			output.PrintTableHeader("Time", "From", "To", "Source")
+			for _, e := range entries {
+				output.PrintTableRow(e.Time.Local().Format(time.DateTime), orNone(e.From), e.To, historySource(e))
+			}
+		}, func() {
+			for _, e := range entries {
+				fmt.Printf("%s %s -> %s (%s)\n", e.Time.Local().Format(time.DateTime), orNone(e.From), e.To, historySource(e))
+			}
+		})
+	},
+}
+
+// historySource 返回来源及其说明，例如 "alias stable"
+func historySource(e version.HistoryEntry) string {
+	if e.Detail == "" {
+		return e.Source
+	}
+	return e.Source + " " + e.Detail
+}
+
+// orNone 在切换前没有版本时显示 none
+func orNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().IntVarP(&flagHistoryLimit, "limit", "n", 0, "show only the last N switches")
+}
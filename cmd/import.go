@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Install the versions listed in a manifest",
+	Long: `Install every Go version listed in a manifest written by 'gvm export',
+then restore its named links and active version. Versions that are already
+installed are skipped.
+
+A plain text file with one version per line is accepted as well; lines
+starting with # are ignored.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := readManifest(args[0])
+		if err != nil {
+			return err
+		}
+		// 清单中的镜像只在用户没有配置镜像时使用
+		if m.Mirror != "" && config.ResolveSettings().Mirror == config.DefaultMirror {
+			config.OverrideSettings(config.Settings{Mirror: m.Mirror})
+			output.PrintInfo(fmt.Sprintf("Using mirror %s from the manifest", m.Mirror))
+		}
+
+		vm := version.New()
+		// 激活版本由清单决定，安装过程中不自动切换
+		flagNoActivate = true
+
+		var missing []string
+		for _, v := range m.Versions {
+			if !strings.HasPrefix(v, "go") {
+				v = "go" + v
+			}
+			if installed, _ := vm.IsVersionInstalled(v); installed {
+				output.PrintInfo(fmt.Sprintf("Go %s is already installed", v))
+				continue
+			}
+			missing = append(missing, v)
+		}
+
+		switch len(missing) {
+		case 0:
+		case 1:
+			if _, err := installOne(vm, missing[0], version.InstallOptions{}, ""); err != nil {
+				return err
+			}
+		default:
+			if err := installMany(vm, missing, version.InstallOptions{}); err != nil {
+				return err
+			}
+		}
+
+		names := make([]string, 0, len(m.Links))
+		for name := range m.Links {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := vm.LinkVersion(m.Links[name], name); err != nil {
+				output.PrintWarning(fmt.Sprintf("Failed to restore link %s: %s", name, err.Error()))
+			}
+		}
+
+		if m.Current != "" {
+			if err := vm.UseVersionWithOptions(m.Current, version.UseOptions{Source: version.SwitchImport, Detail: args[0]}); err != nil {
+				return fmt.Errorf("failed to switch to version %s: %w", m.Current, err)
+			}
+			output.PrintSuccess(fmt.Sprintf("Now using Go %s", m.Current))
+		}
+		return nil
+	},
+}
+
+// readManifest 读取 export 生成的 JSON 清单；内容不是 JSON 时按每行一个版本解析
+func readManifest(path string) (*manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m manifest
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &m); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+		}
+		return &m, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m.Versions = append(m.Versions, line)
+	}
+	return &m, scanner.Err()
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
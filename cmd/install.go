@@ -1,52 +1,203 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
+	"github.com/philokun/gvm/internal/config"
 	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
 	"github.com/philokun/gvm/internal/version"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagActivate   bool
+	flagNoActivate bool
+)
+
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install [version]",
-	Short: "Install a specific Go version",
+	Use:   "install [version...]",
+	Short: "Install one or more Go versions",
 	Long: `Install a specific version of Go. 
 	
 You can specify the version as:
 - full version: go1.21.5
 - short version: 1.21.5
-- latest: installs the latest stable version`,
-	Args: cobra.ExactArgs(1), // 确保只接收一个版本参数
-	RunE: func(cmd *cobra.Command, args []string) error {
-		versionStr := args[0] // 获取版本参数
+- latest: installs the latest stable version
 
+Several versions can be installed at once (gvm install 1.21.6 1.22.0); a
+summary is printed at the end and the command fails if any install failed.
+
+If gvm has no active version yet, the first installed version is activated
+automatically; use --activate or --no-activate to override.
+
+Use --from-gomod <path> to install the version declared by the toolchain
+(or go) directive of a go.mod file; without a version argument the go.mod in
+the current directory is used. A go directive with only a minor version
+(go 1.21) resolves to the latest available patch release of that series, even
+when an older patch is already installed. --from-file <path> installs the
+version in a .go-version file (1.21.5, go1.21.5, v1.21.5 or a series such as
+1.21, which resolves the same way).`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// 使用 --from-gomod 或 --from-file 时不需要版本参数；不带版本时使用当前目录的 go.mod
+		if versionFile(cmd) != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		vm := version.New()
 
-		// 处理 latest 别名
-		lower := strings.ToLower(strings.TrimSpace(versionStr))
-		if lower == "latest" || lower == "go latest" || lower == "golatest" {
-			v, err := vm.GetLatestStable()
+		if len(args) == 0 && versionFile(cmd) == "" {
+			if !utils.FileExists(version.GoModFileName) {
+				return fmt.Errorf("a version is required: no version given and no %s in the current directory", version.GoModFileName)
+			}
+			// 与 --from-gomod 相同：解析版本并提示来源
+			if err := cmd.Flags().Set("from-gomod", version.GoModFileName); err != nil {
+				return err
+			}
+		}
+
+		versions := args
+		if versionFile(cmd) != "" {
+			v, err := resolveVersionArg(cmd, args, flagQuiet, false)
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to resolve latest version: %s", err.Error()))
 				return err
 			}
-			versionStr = v
-		} else {
-			// 标准化版本号格式，确保以 "go" 开头
-			if !strings.HasPrefix(versionStr, "go") {
-				versionStr = "go" + versionStr
+			versions = []string{v}
+		}
+
+		checksum, _ := cmd.Flags().GetString("checksum")
+		checksum = strings.ToLower(strings.TrimSpace(checksum))
+		if checksum != "" && !isSHA256Hex(checksum) {
+			return fmt.Errorf("invalid --checksum %q: expected 64 hex characters", checksum)
+		}
+		if checksum != "" && len(versions) > 1 {
+			return fmt.Errorf("--checksum can only be used when installing a single version")
+		}
+
+		archived, _ := cmd.Flags().GetBool("archived")
+		noResume, _ := cmd.Flags().GetBool("no-resume")
+		parts, _ := cmd.Flags().GetInt("limit-download-parts")
+		if parts < 1 {
+			return fmt.Errorf("--limit-download-parts must be at least 1")
+		}
+		retryMirror, _ := cmd.Flags().GetBool("retry-mirror-on-checksum")
+		noValidate, _ := cmd.Flags().GetBool("no-validate")
+		force, _ := cmd.Flags().GetBool("force")
+		validateTimeout, _ := cmd.Flags().GetDuration("validate-timeout")
+		noSrc, _ := cmd.Flags().GetBool("no-src")
+		// --with-src 默认为 true，只有显式给出时才与 --no-src 冲突；--with-src=false 等同 --no-src
+		if cmd.Flags().Changed("with-src") {
+			withSrc, _ := cmd.Flags().GetBool("with-src")
+			if withSrc && noSrc {
+				return fmt.Errorf("--with-src and --no-src cannot be used together")
+			}
+			noSrc = noSrc || !withSrc
+		}
+		if noSrc {
+			output.PrintWarning("Installing without src/: 'go build', 'go test' and 'go vet' need the standard library sources and will fail; use this only for images that run prebuilt tools or 'go version'")
+		}
+		opts := version.InstallOptions{
+			Checksum:        checksum,
+			Archived:        archived,
+			NoResume:        noResume,
+			Parts:           parts,
+			NoValidate:      noValidate,
+			Force:           force,
+			ValidateTimeout: validateTimeout,
+			NoSrc:           noSrc,
+
+			RetryMirrorOnChecksum: retryMirror,
+		}
+
+		if err := vm.CheckWritable(); err != nil {
+			return err
+		}
+
+		if len(versions) == 1 {
+			_, err := installOne(vm, versions[0], opts, "")
+			return err
+		}
+		return installMany(vm, versions, opts)
+	},
+}
+
+// installResult 记录批量安装中单个版本的结果
+type installResult struct {
+	version  string
+	err      error
+	duration time.Duration
+}
+
+// installMany 依次安装多个版本，显示进度并在最后输出汇总表
+func installMany(vm *version.VersionManager, versions []string, opts version.InstallOptions) error {
+	start := time.Now()
+	results := make([]installResult, 0, len(versions))
+	for i, v := range versions {
+		prefix := fmt.Sprintf("[%d/%d] ", i+1, len(versions))
+		began := time.Now()
+		resolved, err := installOne(vm, v, opts, prefix)
+		if resolved == "" {
+			resolved = v
+		}
+		results = append(results, installResult{version: resolved, err: err, duration: time.Since(began)})
+	}
+
+	failed := 0
+	output.PrintHeader("Install summary")
+	output.PrintTableHeader("Version", "Result", "Time")
+	for _, r := range results {
+		result := "ok"
+		if r.err != nil {
+			result = "failed"
+			failed++
+		}
+		output.PrintTableRow(r.version, result, r.duration.Round(time.Second).String())
+	}
+	fmt.Printf("\n%d succeeded, %d failed in %s\n", len(results)-failed, failed, time.Since(start).Round(time.Second))
+
+	if failed > 0 {
+		for _, r := range results {
+			if r.err != nil {
+				output.PrintError(fmt.Sprintf("%s: %s", r.version, r.err.Error()))
 			}
 		}
+		return fmt.Errorf("%d of %d installs failed", failed, len(results))
+	}
+	return nil
+}
 
-		// 验证版本是否在可用版本列表中（包括不稳定的版本）
+// installOne 解析并安装单个版本，prefix 用于批量安装时显示进度；返回解析后的版本号
+func installOne(vm *version.VersionManager, versionStr string, opts version.InstallOptions, prefix string) (string, error) {
+	// 处理 latest 别名
+	lower := strings.ToLower(strings.TrimSpace(versionStr))
+	if lower == "latest" || lower == "go latest" || lower == "golatest" {
+		v, err := vm.GetLatestStable()
+		if err != nil {
+			output.PrintError(fmt.Sprintf("Failed to resolve latest version: %s", err.Error()))
+			return "", err
+		}
+		versionStr = v
+	} else {
+		// 标准化版本号格式，确保以 "go" 开头
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+	}
+
+	// 验证版本是否在可用版本列表中（包括不稳定的版本）；--archived 时跳过该检查，
+	// 指定 --checksum 时由 InstallVersionWithOptions 直接下载，同时查询版本列表并在解压前检查；
+	// GVM_SOURCE=goproxy 时版本是否存在由模块代理决定
+	if !opts.Archived && opts.Checksum == "" && config.ResolveSettings().Source != config.SourceGoProxy {
 		availableVersions, err := vm.GetAvailableVersions()
 		if err != nil {
 			output.PrintError(fmt.Sprintf("Failed to fetch available versions: %s", err.Error()))
-			return err
+			return versionStr, err
 		}
 
 		versionFound := false
@@ -58,33 +209,162 @@ You can specify the version as:
 		}
 
 		if !versionFound {
-			return fmt.Errorf("version %s not found in available versions. Use 'gvm available' to see all available versions", versionStr)
+			return versionStr, &version.Error{
+				Code: version.CodeVersionNotFound,
+				Err:  fmt.Errorf("version %s not found in available versions. Use 'gvm available' to see all available versions, or pass --archived to install an archived release", versionStr),
+			}
 		}
-		// 创建 VersionManager 实例
-		// 打印安装进度
-		output.PrintProgress(fmt.Sprintf("Installing Go %s...", versionStr))
+	}
+	// 打印安装进度；以 uninstall --keep-config 删除的版本提示为重新安装
+	action := "Installing"
+	if cfg, err := config.Load(); err == nil && cfg.Versions[versionStr].Removed {
+		action = "Reinstalling"
+	}
+	output.PrintProgress(fmt.Sprintf("%s%s Go %s...", prefix, action, versionStr))
 
-		// 安装 Go 版本
-		if err := vm.InstallVersion(versionStr); err != nil {
-			output.PrintError(fmt.Sprintf("Failed to install version %s: %s", versionStr, err.Error()))
-			return err
+	// 安装 Go 版本；--retry-mirror-on-checksum 时记录最终提供安装包的镜像
+	var report version.InstallReport
+	if opts.RetryMirrorOnChecksum {
+		opts.Report = &report
+	}
+	if err := vm.InstallVersionWithOptions(versionStr, opts); err != nil {
+		output.PrintError(fmt.Sprintf("Failed to install version %s: %s", versionStr, err.Error()))
+		if hint := installFailureHint(err); hint != "" {
+			output.PrintInfo(hint)
 		}
-		// 打印安装成功信息
+		return versionStr, err
+	}
+	// 打印安装成功信息
+	switch {
+	case report.Mirror != "":
+		output.PrintSuccess(fmt.Sprintf("Successfully installed Go %s (archive from %s)", versionStr, report.Mirror))
+	case report.Cached:
+		output.PrintSuccess(fmt.Sprintf("Successfully installed Go %s (archive from the download cache)", versionStr))
+	default:
 		output.PrintSuccess(fmt.Sprintf("Successfully installed Go %s", versionStr))
-		// 打印切换提示信息
-		output.PrintInfo(fmt.Sprintf("Use 'gvm use %s' to switch to this version", versionStr))
+	}
 
-		return nil
-	},
+	// 没有激活版本时（或指定 --activate）自动切换到新安装的版本
+	if shouldActivate() {
+		if err := vm.UseVersionWithOptions(versionStr, version.UseOptions{Source: version.SwitchInstall}); err != nil {
+			output.PrintWarning(fmt.Sprintf("Installed but failed to activate Go %s: %s", versionStr, err.Error()))
+		} else {
+			output.PrintSuccess(fmt.Sprintf("Now using Go %s", versionStr))
+			return versionStr, nil
+		}
+	}
+	// 打印切换提示信息
+	output.PrintInfo(fmt.Sprintf("Use 'gvm use %s' to switch to this version", versionStr))
+
+	return versionStr, nil
+}
+
+// shouldActivate 判断安装完成后是否自动切换：--no-activate 优先，其次 --activate，
+// 默认只在 gvm 尚未激活任何已安装版本时切换
+func shouldActivate() bool {
+	if flagNoActivate {
+		return false
+	}
+	if flagActivate {
+		return true
+	}
+	current, err := config.GetCurrentVersion()
+	if err != nil {
+		return false
+	}
+	if current == "" {
+		return true
+	}
+	installed, _ := version.New().IsVersionInstalled(current)
+	return !installed
+}
+
+// installFailureHint 根据安装失败的阶段给出建议
+func installFailureHint(err error) string {
+	switch {
+	case errors.Is(err, version.ErrDownload):
+		return "The download failed; check your network and retry, or try another mirror with --mirror"
+	case errors.Is(err, version.ErrChecksum):
+		return "The archive does not match its checksum; the mirror may be serving corrupt or tampered files; with several mirrors configured, --retry-mirror-on-checksum tries the next one"
+	case errors.Is(err, version.ErrExtract):
+		return "Extraction failed; the disk may be full or the archive corrupt"
+	case errors.Is(err, version.ErrValidate):
+		return "The extracted toolchain looks incomplete; use --no-validate for patched or renamed distributions"
+	}
+	return ""
+}
+
+// versionFile 返回 --from-gomod 或 --from-file 指定的文件，都未指定时返回空字符串
+func versionFile(cmd *cobra.Command) string {
+	if gomod, _ := cmd.Flags().GetString("from-gomod"); gomod != "" {
+		return gomod
+	}
+	file, _ := cmd.Flags().GetString("from-file")
+	return file
+}
+
+// resolveVersionArg 返回命令的版本参数；指定 --from-gomod 时从 go.mod 解析版本，
+// 指定 --from-file 时从 .go-version 等版本文件解析。系列（如 1.21）在 preferInstalled 为 true 时
+// 优先解析为已安装的补丁版本（gvm use），否则解析为可用的最新补丁版本（gvm install）。
+// quiet 为 false 时提示解析结果。
+func resolveVersionArg(cmd *cobra.Command, args []string, quiet, preferInstalled bool) (string, error) {
+	gomod, _ := cmd.Flags().GetString("from-gomod")
+	file, _ := cmd.Flags().GetString("from-file")
+	var v string
+	var err error
+	switch {
+	case gomod != "" && file != "":
+		return "", fmt.Errorf("--from-gomod and --from-file cannot be used together")
+	case gomod != "":
+		v, err = version.New().ResolveGoMod(gomod, preferInstalled)
+	case file != "":
+		v, err = version.New().ResolveVersionFile(file, preferInstalled)
+	default:
+		return args[0], nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !quiet {
+		output.PrintInfo(fmt.Sprintf("Resolved Go %s from %s", v, versionFile(cmd)))
+	}
+	return v, nil
+}
+
+// isSHA256Hex 检查字符串是否为 64 位十六进制 SHA256 摘要
+func isSHA256Hex(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
 }
 
 func init() {
 	rootCmd.AddCommand(installCmd)
-	installCmd.Flags().String("mirror", "", "override download mirror base URL")
+	installCmd.Flags().String("mirror", "", "download mirror base URL (GVM_DL_MIRROR takes precedence when set)")
+	installCmd.Flags().String("checksum", "", "expected SHA256 of the package (overrides the value from the versions JSON)")
+	installCmd.Flags().Bool("archived", false, "allow installing a version missing from the versions JSON (requires --checksum or a .sha256 sidecar on the mirror)")
+	installCmd.Flags().String("from-gomod", "", "install the version declared in the given go.mod")
+	installCmd.Flags().String("from-file", "", "install the version named in the given .go-version file")
+	installCmd.Flags().Bool("no-validate", false, "skip the VERSION check and the 'go version' run for patched or renamed toolchains (the go binary must still exist)")
+	installCmd.Flags().Duration("validate-timeout", version.DefaultValidateTimeout, "time limit for running 'go version' after extraction")
+	installCmd.Flags().Bool("with-src", true, "extract the src/ tree with the standard library sources (default)")
+	installCmd.Flags().Bool("no-src", false, "do not extract src/ to save space; the toolchain cannot build packages without it")
+	installCmd.Flags().Bool("force", false, "replace an existing Go installation not managed by gvm at the target location")
+	installCmd.Flags().BoolVar(&flagActivate, "activate", false, "switch to the installed version even if another version is active")
+	installCmd.Flags().BoolVar(&flagNoActivate, "no-activate", false, "never switch to the installed version automatically")
+	installCmd.Flags().Bool("no-resume", false, "discard any partial download and start from scratch")
+	installCmd.Flags().Bool("retry-mirror-on-checksum", false, "when a mirror serves an archive that fails the SHA256 check, quarantine it and try the next mirror instead of failing")
+	installCmd.Flags().Int("limit-download-parts", 1, "download the archive over up to N parallel connections when the mirror supports ranges (1 downloads sequentially)")
 	installCmd.PreRun = func(cmd *cobra.Command, args []string) {
 		m, _ := cmd.Flags().GetString("mirror")
 		if strings.TrimSpace(m) != "" {
-			os.Setenv("GVM_DL_MIRROR", strings.TrimRight(m, "/"))
+			config.OverrideSettings(config.Settings{Mirror: m})
 		}
 	}
 }
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagLinkList   bool
+	flagLinkRemove bool
+)
+
+// linkCmd represents the link command
+var linkCmd = &cobra.Command{
+	Use:   "link <version> <name>",
+	Short: "Expose a Go version under a custom command name",
+	Long: `Create a named shim in ~/.gvm/shims so that a specific Go version can be
+invoked directly, side by side with the active one.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		switch {
+		case flagLinkList:
+			return cobra.NoArgs(cmd, args)
+		case flagLinkRemove:
+			return cobra.ExactArgs(1)(cmd, args)
+		default:
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm := version.New()
+
+		if flagLinkList {
+			links, err := config.GetLinks()
+			if err != nil {
+				return err
+			}
+			if len(links) == 0 {
+				output.PrintInfo("No named links. Use 'gvm link <version> <name>' to create one.")
+				return nil
+			}
+			names := make([]string, 0, len(links))
+			for name := range links {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("%s -> %s\n", name, links[name])
+			}
+			return nil
+		}
+
+		if flagLinkRemove {
+			if err := vm.UnlinkVersion(args[0]); err != nil {
+				return fmt.Errorf("failed to remove link %s: %w", args[0], err)
+			}
+			output.PrintSuccess(fmt.Sprintf("Removed link %s", args[0]))
+			return nil
+		}
+
+		versionStr, name := args[0], args[1]
+		// 标准化版本号格式
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+
+		if err := vm.LinkVersion(versionStr, name); err != nil {
+			return fmt.Errorf("failed to link %s as %s: %w", versionStr, name, err)
+		}
+		output.PrintSuccess(fmt.Sprintf("Go %s is now available as '%s'", versionStr, name))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+	linkCmd.Flags().BoolVar(&flagLinkList, "list", false, "list named links")
+	linkCmd.Flags().BoolVar(&flagLinkRemove, "remove", false, "remove the named link")
+}
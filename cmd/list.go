@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/philokun/gvm/internal/output"
@@ -19,69 +20,235 @@ var listCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List installed Go versions",
-	Long:    `List all Go versions that are currently installed on your system.`,
+	Long: `List all Go versions that are currently installed on your system.
+
+With --index the versions installed by gvm are numbered in the order shown;
+'gvm use N' and 'gvm uninstall N' accept these numbers instead of a version.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		vm := version.New()
-		versions, err := vm.GetInstalledVersions()
+		allVersions, err := listedVersions(vm)
 		if err != nil {
-			return fmt.Errorf("failed to get installed versions: %w", err)
+			return err
 		}
 
-		current, _ := vm.GetCurrentVersion()
-		sysVer := detectSystemGo(vm)
-
-		// 收集所有版本（系统版本 + gvm 安装的版本）
-		allVersions := make([]versionInfo, 0)
-
-		// 添加系统版本
-		if sysVer != "" {
-			isCurrent := current == "system"
-			allVersions = append(allVersions, versionInfo{
-				version: sysVer,
-				source:  "system",
-				current: isCurrent,
-			})
-		}
-
-		// 添加 gvm 安装的版本
-		for _, v := range versions {
-			isCurrent := v == current
-			allVersions = append(allVersions, versionInfo{
-				version: v,
-				source:  "gvm",
-				current: isCurrent,
-			})
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
 		}
 
 		// 如果没有版本，显示提示
-		if len(allVersions) == 0 {
+		if len(allVersions) == 0 && format != output.FormatJSON {
 			output.PrintWarning("No Go found. Use 'gvm install <version>' to install one.")
 			return nil
 		}
 
-		// 排序：当前版本在前，其他版本按版本号降序
-		sortVersions(allVersions)
-
-		// 仿照 nvm 的显示方式：简单列表，当前版本用 * 标记
+		entries := make([]listEntry, 0, len(allVersions))
 		for _, v := range allVersions {
-			if v.current {
-				// 当前版本：显示 * 和详细信息
-				arch := runtime.GOARCH
-				fmt.Printf("* %s (Currently using %s executable)\n", v.version, arch)
+			entries = append(entries, listEntry{Version: v.version, Source: v.source, Current: v.current, Index: v.index})
+		}
+
+		if flagListTree {
+			tree := groupBySeries(entries)
+			return output.Render(format, tree, nil, func() {
+				printSeriesTree(tree)
+			})
+		}
+
+		return output.Render(format, entries, func() {
+			if flagListIndex {
+				output.PrintTableHeader("#", "Version", "Source", "Current")
 			} else {
-				// 其他版本：只显示版本号
-				fmt.Println(v.version)
+				output.PrintTableHeader("Version", "Source", "Current")
+			}
+			for _, v := range allVersions {
+				mark := ""
+				if v.current {
+					mark = "*"
+				}
+				if flagListIndex {
+					output.PrintTableRow(indexLabel(v.index), v.version, v.source, mark)
+				} else {
+					output.PrintTableRow(v.version, v.source, mark)
+				}
 			}
+		}, func() {
+			// 仿照 nvm 的显示方式：简单列表，当前版本用 * 标记
+			for _, v := range allVersions {
+				if flagListIndex {
+					fmt.Printf("%3s  ", indexLabel(v.index))
+				}
+				if v.current {
+					// 当前版本：显示 * 和详细信息
+					arch := runtime.GOARCH
+					fmt.Printf("* %s (Currently using %s executable)\n", v.version, arch)
+				} else if v.source == "removed" {
+					fmt.Printf("%s (removed, reinstallable)\n", v.version)
+				} else {
+					// 其他版本：只显示版本号
+					fmt.Println(v.version)
+				}
+			}
+		})
+	},
+}
+
+// listedVersions 返回 list 显示的所有版本（系统版本、gvm 安装的版本与已删除的版本），
+// 按显示顺序排列，gvm 安装的版本按该顺序从 1 开始编号
+func listedVersions(vm *version.VersionManager) ([]versionInfo, error) {
+	versions, err := vm.GetInstalledVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installed versions: %w", err)
+	}
+
+	current, _ := vm.GetCurrentVersion()
+	sysVer := detectSystemGo(vm)
+
+	// 收集所有版本（系统版本 + gvm 安装的版本）
+	allVersions := make([]versionInfo, 0)
+
+	// 添加系统版本
+	if sysVer != "" {
+		isCurrent := current == "system"
+		allVersions = append(allVersions, versionInfo{
+			version: sysVer,
+			source:  "system",
+			current: isCurrent,
+		})
+	}
+
+	// 添加 gvm 安装的版本
+	for _, v := range versions {
+		isCurrent := v == current
+		allVersions = append(allVersions, versionInfo{
+			version: v,
+			source:  "gvm",
+			current: isCurrent,
+		})
+	}
+
+	// 添加以 uninstall --keep-config 删除的版本
+	removed, _ := vm.GetRemovedVersions()
+	for _, v := range removed {
+		allVersions = append(allVersions, versionInfo{
+			version: v,
+			source:  "removed",
+		})
+	}
+
+	// 排序：当前版本在前，其他版本按版本号降序
+	sortVersions(allVersions)
+
+	n := 0
+	for i := range allVersions {
+		if allVersions[i].source == "gvm" {
+			n++
+			allVersions[i].index = n
 		}
+	}
+	return allVersions, nil
+}
 
-		return nil
-	},
+// maxVersionIndex 是 use、uninstall 接受的最大编号；更大的整数按版本号处理
+const maxVersionIndex = 99
+
+// versionByIndex 将 use、uninstall 的参数解析为 list --index 中的编号：参数是 1 到 maxVersionIndex 之间的整数时
+// 返回对应的已安装版本。除了无法从 go.dev 安装的 go1 之外，Go 的版本号都包含点（如 1.21），
+// 因此编号不会与版本号混淆；确实要指定 go1 时写完整的 go1
+func versionByIndex(vm *version.VersionManager, arg string) (string, bool, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > maxVersionIndex || strconv.Itoa(n) != arg {
+		return "", false, nil
+	}
+	listed, err := listedVersions(vm)
+	if err != nil {
+		return "", true, err
+	}
+	count := 0
+	for _, v := range listed {
+		if v.index == n {
+			return v.version, true, nil
+		}
+		if v.index > 0 {
+			count++
+		}
+	}
+	if count == 0 {
+		return "", true, fmt.Errorf("no versions installed by gvm; %s is not a valid index", arg)
+	}
+	return "", true, fmt.Errorf("index %d is out of range: 'gvm list --index' shows %d installed versions", n, count)
+}
+
+// indexLabel 返回 list --index 中的编号，未编号（系统版本、已删除的版本）时为 -
+func indexLabel(index int) string {
+	if index == 0 {
+		return "-"
+	}
+	return strconv.Itoa(index)
 }
 
 type versionInfo struct {
 	version string
 	source  string
 	current bool
+	index   int // gvm 安装的版本在列表中的编号，从 1 开始；其他来源为 0
+}
+
+// listEntry 是 list 命令的 JSON 输出结构
+type listEntry struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	Current bool   `json:"current"`
+	Index   int    `json:"index,omitempty"` // 可传给 use、uninstall 的编号，只有 gvm 安装的版本才有
+}
+
+// seriesGroup 是 list --tree 中一个次版本系列的分组
+type seriesGroup struct {
+	Series   string      `json:"series"`
+	Versions []listEntry `json:"versions"`
+}
+
+// groupBySeries 按次版本系列分组，系列与组内版本均按版本号降序
+func groupBySeries(entries []listEntry) []seriesGroup {
+	index := make(map[string]int)
+	groups := []seriesGroup{}
+	for _, e := range entries {
+		major, minor, _ := parseVersionNumber(e.Version)
+		series := fmt.Sprintf("%d.%d", major, minor)
+		i, ok := index[series]
+		if !ok {
+			i = len(groups)
+			index[series] = i
+			groups = append(groups, seriesGroup{Series: series})
+		}
+		groups[i].Versions = append(groups[i].Versions, e)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return version.CompareVersions(groups[i].Series, groups[j].Series) > 0
+	})
+	for _, g := range groups {
+		sort.Slice(g.Versions, func(i, j int) bool {
+			return version.CompareVersions(g.Versions[i].Version, g.Versions[j].Version) > 0
+		})
+	}
+	return groups
+}
+
+// printSeriesTree 以 "1.21: go1.21.6*, go1.21.5" 的形式打印分组，当前版本用 * 标记
+func printSeriesTree(groups []seriesGroup) {
+	for _, g := range groups {
+		names := make([]string, 0, len(g.Versions))
+		for _, v := range g.Versions {
+			name := v.Version
+			if v.Source == "system" || v.Source == "removed" {
+				name += " (" + v.Source + ")"
+			}
+			if v.Current {
+				name += "*"
+			}
+			names = append(names, name)
+		}
+		fmt.Printf("%s: %s\n", g.Series, strings.Join(names, ", "))
+	}
 }
 
 // sortVersions 排序版本：当前版本在前，其他版本按版本号降序
@@ -94,13 +261,20 @@ func sortVersions(versions []versionInfo) {
 		if !versions[i].current && versions[j].current {
 			return false
 		}
-		// 其他版本按版本号降序
-		return versions[i].version > versions[j].version
+		// 其他版本按版本号降序（语义比较，go1.9 排在 go1.21 之后）
+		return version.CompareVersions(versions[i].version, versions[j].version) > 0
 	})
 }
 
+var (
+	flagListTree  bool
+	flagListIndex bool
+)
+
 func init() {
 	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().BoolVar(&flagListTree, "tree", false, "group installed versions by minor series")
+	listCmd.Flags().BoolVarP(&flagListIndex, "index", "i", false, "number the installed versions for 'gvm use N' and 'gvm uninstall N'")
 }
 
 func detectSystemGo(vm *version.VersionManager) string {
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagPruneJobs   int
+	flagPruneDryRun bool
+	flagPruneYes    bool
+)
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove superseded patch versions",
+	Long: `Remove installed versions that have a newer patch release of the same minor
+series installed (e.g. go1.21.5 when go1.21.6 is installed). The active
+version is never removed.
+
+Deletions run in parallel (see --jobs); config.json is updated once after all
+deletions have finished, for the versions that were actually removed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vm := version.New()
+		candidates, err := vm.PruneCandidates()
+		if err != nil {
+			return fmt.Errorf("failed to get installed versions: %w", err)
+		}
+		if len(candidates) == 0 {
+			output.PrintInfo("Nothing to prune")
+			return nil
+		}
+
+		fmt.Println("The following versions will be removed:")
+		for _, v := range candidates {
+			fmt.Printf("  %s\n", v)
+		}
+		if flagPruneDryRun {
+			return nil
+		}
+		if !flagPruneYes && !output.Confirm(fmt.Sprintf("Remove %d version(s)?", len(candidates))) {
+			output.PrintInfo("Aborted")
+			return nil
+		}
+
+		results, err := vm.PruneVersions(candidates, flagPruneJobs)
+		failed := 0
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+				output.PrintError(fmt.Sprintf("%s: %s", r.Version, r.Err.Error()))
+			} else {
+				output.PrintSuccess(fmt.Sprintf("Removed Go %s", r.Version))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d versions could not be removed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().IntVarP(&flagPruneJobs, "jobs", "j", 4, "number of versions to delete in parallel")
+	pruneCmd.Flags().BoolVar(&flagPruneDryRun, "dry-run", false, "only show what would be removed")
+	pruneCmd.Flags().BoolVarP(&flagPruneYes, "yes", "y", false, "do not ask for confirmation")
+}
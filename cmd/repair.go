@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRepairForce bool
+	flagRepairNoSrc bool
+)
+
+// repairCmd represents the repair command
+var repairCmd = &cobra.Command{
+	Use:   "repair <version>",
+	Short: "Re-extract a broken version, from the download cache when possible",
+	Long: `Check an installed version and, if it is broken (for example because the
+extraction was interrupted), remove it and extract it again. When the archive
+is still in the download cache and matches its checksum it is re-extracted
+without downloading; otherwise it is downloaded again. The result says which
+of the two happened.
+
+An intact version is left alone unless --force is given.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstalledVersions(false),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+
+		vm := version.New()
+		versionStr := normalizeVersion(args[0])
+		if err := vm.CheckWritable(); err != nil {
+			return err
+		}
+
+		result, err := vm.RepairVersion(versionStr, flagRepairForce, version.InstallOptions{NoSrc: flagRepairNoSrc})
+		if err != nil {
+			return fmt.Errorf("failed to repair %s: %w", versionStr, err)
+		}
+		return output.Render(format, result, nil, func() {
+			if result.Problem != "" {
+				output.PrintInfo("Problem found: " + result.Problem)
+			}
+			switch {
+			case !result.Repaired:
+				output.PrintSuccess(fmt.Sprintf("Go %s is intact; nothing to repair (use --force to re-extract anyway)", versionStr))
+			case result.FromCache:
+				output.PrintSuccess(fmt.Sprintf("Repaired Go %s from the cached archive", versionStr))
+			default:
+				output.PrintSuccess(fmt.Sprintf("Repaired Go %s by downloading it again", versionStr))
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(repairCmd)
+	repairCmd.Flags().BoolVar(&flagRepairForce, "force", false, "re-extract even if the version passes the check")
+	repairCmd.Flags().BoolVar(&flagRepairNoSrc, "no-src", false, "do not extract src/ (see 'gvm install --no-src')")
+}
@@ -1,11 +1,36 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/philokun/gvm/internal/version"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// flagOutput 全局输出格式（table、plain、json），为空时使用各命令的默认格式
+	flagOutput string
+	// flagJSONErrors 以 JSON 形式向 stderr 输出错误
+	flagJSONErrors bool
+	// flagQuiet 成功时不输出提示信息，只输出错误
+	flagQuiet bool
+	// flagVerbose 输出重定向等诊断信息
+	flagVerbose bool
+	// flagNoProgress 不显示下载进度；CI=true 时默认开启
+	flagNoProgress bool
+	// flagShell 指定要修改配置文件的 shell，为空时自动检测
+	flagShell string
+	// flagASCII 只输出 ASCII 字符且不使用颜色；NO_COLOR 或输出不是终端时默认开启
+	flagASCII bool
+
+	// updateNotice 在命令结束后返回应提示的 gvm 新版本，未检查时为 nil
+	updateNotice func() string
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "gvm",
@@ -24,6 +49,45 @@ Examples:
   gvm available              # List available versions
 
 For more information, visit: https://github.com/philokun/gvm`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// --json-errors 或 --output json 时，错误由 Execute 统一以 JSON 输出
+		if f, _ := output.ParseFormat(flagOutput, ""); flagJSONErrors || f == output.FormatJSON {
+			output.SetJSONErrors(true)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		output.SetVerbose(flagVerbose)
+		utils.SetShellOverride(flagShell)
+		// --ascii > GVM_ASCII > 自动检测：NO_COLOR 或输出不是终端时完全使用 ASCII，
+		// 终端不支持 UTF-8 时只替换符号、保留颜色
+		ascii, explicit := flagASCII, cmd.Flags().Changed("ascii")
+		if !explicit {
+			ascii, explicit = output.ASCIIFromEnv()
+		}
+		switch {
+		case explicit:
+			output.SetASCII(ascii)
+		case output.NoColor() || !output.StdoutIsTerminal():
+			output.SetASCII(true)
+		default:
+			output.SetASCII(false)
+			output.SetASCIISymbols(!output.SupportsUTF8())
+		}
+		// CI 日志中 \r 刷新的进度会变成大量重复行，未显式指定时在 CI 中关闭进度；--quiet 同样不显示进度
+		if cmd.Flags().Changed("no-progress") {
+			output.SetProgress(!flagNoProgress && !flagQuiet)
+		} else {
+			output.SetProgress(!output.IsCI() && !flagQuiet)
+		}
+		// --quiet 时出错只输出错误本身
+		if flagQuiet {
+			cmd.SilenceUsage = true
+		}
+		// 开启 notify-updates 时在后台检查新版本；只在交互式终端中提示，不干扰脚本、补全与 JSON 输出
+		if !flagQuiet && !output.JSONErrors() && !output.IsCI() && output.StderrIsTerminal() && !strings.HasPrefix(cmd.Name(), "__") {
+			updateNotice = version.StartUpdateCheck()
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help() // 显示帮助信息
 	},
@@ -32,11 +96,32 @@ For more information, visit: https://github.com/philokun/gvm`,
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		if output.JSONErrors() {
+			output.PrintErrorJSON(err.Error(), version.ErrorCode(err))
+		}
 		os.Exit(1)
 	}
+	if updateNotice != nil && !output.JSONErrors() {
+		if latest := updateNotice(); latest != "" {
+			output.PrintNotice(fmt.Sprintf("gvm %s is available (you have %s): %s", latest, version.GVMVersion(), version.ReleasesPage))
+		}
+	}
+}
+
+// outputFormat 返回当前命令应使用的输出格式，未指定 --output 时使用 def
+func outputFormat(def output.Format) (output.Format, error) {
+	return output.ParseFormat(flagOutput, def)
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "", "output format: table, plain or json")
+	rootCmd.PersistentFlags().BoolVar(&flagJSONErrors, "json-errors", false, "print errors as JSON to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&flagQuiet, "quiet", "q", false, "only print errors")
+	rootCmd.PersistentFlags().BoolVar(&flagVerbose, "verbose", false, "print diagnostic details such as HTTP redirects")
+	rootCmd.PersistentFlags().BoolVar(&flagASCII, "ascii", false, "print only ASCII without colors (default when GVM_ASCII=1, NO_COLOR is set or output is not a terminal; symbols alone fall back to ASCII when the terminal is not UTF-8)")
+	rootCmd.PersistentFlags().StringVar(&flagShell, "shell", "", "shell whose config file gvm edits: bash, zsh or fish (default: the parent process's shell, then $SHELL)")
+	rootCmd.PersistentFlags().BoolVar(&flagNoProgress, "no-progress", false, "do not show download progress but keep status messages (default when CI=true)")
+
 	// 移除默认的toggle标志
 	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	// rootCmd.Flags().MarkHidden("toggle") // 隐藏这个标志，因为我们不需要它
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// semverCmd represents the semver command
+var semverCmd = &cobra.Command{
+	Use:   "semver <version>",
+	Short: "Parse a Go version number",
+	Long: `Parse a Go version number with the same rules gvm uses internally and print
+its parts, so scripts do not need to reimplement Go version handling:
+
+  gvm semver 1.22rc1            # go1.22rc1: major 1, minor 22, patch 0, prerelease rc1
+  gvm semver go1.21.5 -o json   # machine-readable
+  gvm semver compare 1.21.10 1.21.9   # prints 1
+
+The "go" prefix is optional. Invalid versions exit non-zero.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		v, err := version.ParseSemVer(args[0])
+		if err != nil {
+			return err
+		}
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		return output.Render(format, v, func() {
+			output.PrintTableHeader("Version", "Major", "Minor", "Patch", "Prerelease", "Series")
+			output.PrintTableRow(v.Version, fmt.Sprint(v.Major), fmt.Sprint(v.Minor), fmt.Sprint(v.Patch), v.Prerelease, v.Series)
+		}, func() {
+			fmt.Printf("version: %s\n", v.Version)
+			fmt.Printf("major: %d\n", v.Major)
+			fmt.Printf("minor: %d\n", v.Minor)
+			fmt.Printf("patch: %d\n", v.Patch)
+			fmt.Printf("prerelease: %s\n", v.Prerelease)
+			fmt.Printf("series: %s\n", v.Series)
+		})
+	},
+}
+
+var semverCompareCmd = &cobra.Command{
+	Use:   "compare <a> <b>",
+	Short: "Compare two Go versions, printing -1, 0 or 1",
+	Long: `Compare two Go versions and print -1 if a is older than b, 0 if they are
+equal and 1 if a is newer. Prereleases are older than the release of the same
+series (go1.22rc1 < go1.22.0), and go1.20 equals go1.20.0.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		for _, v := range args {
+			if _, err := version.ParseSemVer(v); err != nil {
+				return err
+			}
+		}
+		result := version.CompareVersions(args[0], args[1])
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		return output.Render(format, struct {
+			A      string `json:"a"`
+			B      string `json:"b"`
+			Result int    `json:"result"`
+		}{args[0], args[1], result}, nil, func() {
+			fmt.Println(result)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(semverCmd)
+	semverCmd.AddCommand(semverCompareCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// setupCmd represents the setup command
+var setupCmd = &cobra.Command{
+	Use:     "setup",
+	Aliases: []string{"init"},
+	Short:   "Set up the shims directory and PATH (run once)",
+	Long: `Create ~/.gvm/shims and add it to your PATH through a managed block in your
+shell configuration (for fish, the auto-loaded ~/.config/fish/conf.d/gvm.fish).
+This only needs to be done once; afterwards 'gvm use' simply re-points the
+shims.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shimsDir, err := utils.GetShimsDir()
+		if err != nil {
+			return err
+		}
+		if err := utils.EnsureDir(shimsDir); err != nil {
+			return fmt.Errorf("failed to create shims directory: %w", err)
+		}
+		output.PrintSuccess(fmt.Sprintf("Shims directory ready: %s", shimsDir))
+
+		if runtime.GOOS == "windows" {
+			if err := utils.UpdatePathForWindows(shimsDir); err != nil {
+				return fmt.Errorf("failed to update windows env: %w", err)
+			}
+			output.PrintSuccess("PowerShell profile configured")
+		} else {
+			changed, err := utils.EnsureShellPath(shimsDir)
+			if err != nil {
+				return fmt.Errorf("failed to update shell config: %w", err)
+			}
+			if changed != "" {
+				output.PrintSuccess(fmt.Sprintf("Added gvm block to %s", changed))
+			} else {
+				output.PrintInfo("Shell configuration already up to date")
+			}
+		}
+
+		if utils.PathContains(shimsDir) {
+			output.PrintInfo("Shims directory is already on your PATH")
+		} else {
+			output.PrintInfo(fmt.Sprintf("To activate in this terminal, %s", utils.ActivationHint()))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setupCmd)
+}
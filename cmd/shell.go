@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell <version>",
+	Short: "Start a subshell with a Go version active",
+	Long: `Start an interactive $SHELL in which the given version's GOROOT and bin
+directory come first on PATH. The active version is not changed; leaving the
+subshell with 'exit' returns to the previous environment.
+
+The subshell has GVM_SHELL=1 and GVM_SHELL_VERSION=<version> set, so prompts
+and scripts can tell they are inside a temporary session. On Windows
+PowerShell is started (cmd.exe when PowerShell is not available).`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstalledVersions(true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionStr := args[0]
+		vm := version.New()
+
+		// 命名链接与 gvm alias 定义的别名可以代替版本号
+		if target, ok := vm.ResolveLink(versionStr); ok {
+			versionStr = target
+		} else if target, ok, err := resolveAlias(vm, versionStr, true); err != nil {
+			return err
+		} else if ok {
+			versionStr = target
+		}
+
+		// 标准化版本号格式
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+
+		binPath, err := vm.GetBinPath(versionStr)
+		if err != nil {
+			return err
+		}
+		if os.Getenv("GVM_SHELL") == "1" {
+			output.PrintWarning(fmt.Sprintf("Already inside a gvm shell for %s; starting a nested one", os.Getenv("GVM_SHELL_VERSION")))
+		}
+
+		shell, shellArgs := interactiveShell()
+		child := exec.Command(shell, shellArgs...)
+		child.Env = shellEnv(os.Environ(), binPath, versionStr)
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+
+		output.PrintInfo(fmt.Sprintf("Starting %s with Go %s; type 'exit' to leave", filepath.Base(shell), versionStr))
+		cmd.SilenceUsage = true
+		if err := child.Run(); err != nil {
+			// 子 shell 的退出码原样返回，不视为 gvm 的错误
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			return fmt.Errorf("failed to start %s: %w", shell, err)
+		}
+		return nil
+	},
+}
+
+// interactiveShell 返回要启动的交互式 shell 及其参数
+func interactiveShell() (string, []string) {
+	if runtime.GOOS == "windows" {
+		if ps, err := exec.LookPath("powershell.exe"); err == nil {
+			return ps, []string{"-NoLogo"}
+		}
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec, nil
+		}
+		return "cmd.exe", nil
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell, []string{"-i"}
+	}
+	return "/bin/sh", []string{"-i"}
+}
+
+// shellEnv 基于 environ 构造子 shell 的环境：GOROOT 指向该版本，其 bin 目录置于 PATH 最前，
+// 并设置 GVM_SHELL 与 GVM_SHELL_VERSION
+func shellEnv(environ []string, binPath, versionStr string) []string {
+	env := versionEnv(environ, binPath, "GVM_SHELL", "GVM_SHELL_VERSION")
+	return append(env,
+		"GVM_SHELL=1",
+		"GVM_SHELL_VERSION="+versionStr,
+	)
+}
+
+// versionEnv 基于 environ 构造使用 binPath 所在版本的环境：GOROOT 指向该版本，其 bin 目录置于 PATH 最前；
+// drop 中的变量被移除
+func versionEnv(environ []string, binPath string, drop ...string) []string {
+	env := make([]string, 0, len(environ)+4)
+	path := binPath
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		switch {
+		case strings.EqualFold(key, "PATH"):
+			if value != "" {
+				path = binPath + string(os.PathListSeparator) + value
+			}
+			continue
+		case key == "GOROOT", slices.Contains(drop, key):
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env,
+		"PATH="+path,
+		"GOROOT="+filepath.Dir(binPath),
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
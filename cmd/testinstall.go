@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var flagTestInstallTimeout time.Duration
+
+// testInstallCmd represents the test-install command
+var testInstallCmd = &cobra.Command{
+	Use:   "test-install <version>",
+	Short: "Check that an installed version can build code",
+	Long: `Build a minimal hello world module with the given installed version in a
+temporary directory and report the compiler output if the build fails.
+
+This catches breakage that the existence check in 'gvm current --check' misses,
+such as standard library files lost during a bad extraction. Only installs for
+the current platform can be tested.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeInstalledVersions(true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versionStr := args[0]
+		vm := version.New()
+
+		// 命名链接可以作为版本别名使用
+		if target, ok := vm.ResolveLink(versionStr); ok {
+			versionStr = target
+		}
+
+		// 标准化版本号格式
+		if !strings.HasPrefix(versionStr, "go") {
+			versionStr = "go" + versionStr
+		}
+
+		output.PrintInfo(fmt.Sprintf("Building a test program with Go %s...", versionStr))
+		if err := vm.SmokeTest(versionStr, flagTestInstallTimeout); err != nil {
+			return fmt.Errorf("Go %s failed the build test: %w", versionStr, err)
+		}
+		output.PrintSuccess(fmt.Sprintf("Go %s builds code successfully", versionStr))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testInstallCmd)
+	testInstallCmd.Flags().DurationVar(&flagTestInstallTimeout, "timeout", version.DefaultSmokeTestTimeout, "maximum time to wait for 'go build'")
+}
@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
 	"github.com/philokun/gvm/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -12,30 +16,243 @@ import (
 var uninstallCmd = &cobra.Command{
 	Use:   "uninstall [version]",
 	Short: "Uninstall a specific Go version",
-	Long:  `Remove a specific version of Go from your system.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Remove a specific version of Go from your system.
+
+Versions that named links (see 'gvm link') or aliases (see 'gvm alias')
+still point to are not removed unless --force is given, in which case the
+links and aliases are removed as well.
+
+Removing a version also breaks terminals that use it outside gvm's tracking,
+so it is refused without --force when $GOROOT points into it or (on Linux)
+one of your processes runs a program from it or has GOROOT set to it.
+
+With --keep-config only the files are removed; the version stays recorded in
+config.json and is listed as "removed, reinstallable" until it is installed
+again.
+
+--dry-run shows what would happen without deleting anything: the directory,
+the space it would free, whether it is active, the named links and aliases
+pointing at it and any use outside gvm, and whether the uninstall would be refused. With
+--json (or --output json) this preview, or after a real uninstall the same
+description of what was removed, is printed as a JSON object and errors are
+printed as JSON to stderr:
+
+  gvm uninstall 1.21.5 --dry-run --json
+
+Without a version (or with --interactive) the installed versions are listed
+with numbers to pick from, e.g. "1 3" or "2-4"; the active version is shown
+but cannot be selected. This needs a terminal.
+
+A number from 1 to 99 as the version selects that entry of 'gvm list --index'
+(the active version first, then the others from newest to oldest).`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstalledVersions(false),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		keepConfig, _ := cmd.Flags().GetBool("keep-config")
+		opts := version.UninstallOptions{Force: force, KeepConfig: keepConfig}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		// --json 等价于 --output json：stdout 只输出结果对象，错误以 JSON 输出到 stderr
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			format = output.FormatJSON
+		}
+		jsonOut := format == output.FormatJSON
+		if jsonOut {
+			output.SetJSONErrors(true)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		if len(args) == 0 || interactive {
+			if len(args) > 0 {
+				return fmt.Errorf("--interactive cannot be combined with a version argument")
+			}
+			if dryRun || jsonOut {
+				return fmt.Errorf("--dry-run and --json need a version argument")
+			}
+			if !output.IsTerminal() {
+				return fmt.Errorf("a version is required (interactive selection needs a terminal)")
+			}
+			return uninstallInteractive(version.New(), opts)
+		}
+
 		versionStr := args[0]
+		vm := version.New()
+
+		// gvm alias 定义的别名，其次是 list --index 的编号
+		target, isAlias, err := resolveAlias(vm, versionStr, jsonOut)
+		if err != nil {
+			return err
+		}
+		if isAlias {
+			versionStr = target
+		}
+		target, isIndex, err := versionByIndex(vm, versionStr)
+		if err != nil {
+			return err
+		}
+		if isIndex {
+			if !jsonOut {
+				output.PrintInfo(fmt.Sprintf("#%s in 'gvm list --index' is Go %s", versionStr, target))
+			}
+			versionStr = target
+		}
 
 		// 标准化版本号格式
 		if !strings.HasPrefix(versionStr, "go") {
 			versionStr = "go" + versionStr
 		}
 
-		vm := version.New()
+		if dryRun {
+			plan, err := vm.PlanUninstall(versionStr, opts)
+			if err != nil {
+				return err
+			}
+			return output.Render(format, plan, nil, func() { printUninstallPlan(plan) })
+		}
 
-		fmt.Printf("Uninstalling Go %s...\n", versionStr)
+		// --json 输出被删除的内容，需要在删除前统计
+		var plan *version.UninstallPlan
+		if jsonOut {
+			if plan, err = vm.PlanUninstall(versionStr, opts); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("Uninstalling Go %s...\n", versionStr)
+		}
 
-		if err := vm.UninstallVersion(versionStr); err != nil {
+		if err := vm.UninstallVersionWithOptions(versionStr, opts); err != nil {
 			return fmt.Errorf("failed to uninstall version %s: %w", versionStr, err)
 		}
 
+		if jsonOut {
+			return output.PrintJSON(plan)
+		}
 		fmt.Printf("Successfully uninstalled Go %s\n", versionStr)
 
 		return nil
 	},
 }
 
+// printUninstallPlan 以文本形式输出 uninstall --dry-run 的预览
+func printUninstallPlan(plan *version.UninstallPlan) {
+	if plan.Allowed {
+		fmt.Printf("Would uninstall Go %s\n", plan.Version)
+	} else {
+		fmt.Printf("Would not uninstall Go %s\n", plan.Version)
+	}
+	fmt.Printf("  directory: %s\n", plan.Dir)
+	fmt.Printf("  size:      %s\n", formatSize(plan.Size))
+	if len(plan.Links) > 0 {
+		fmt.Printf("  links:     %s\n", strings.Join(plan.Links, ", "))
+	}
+	if len(plan.Aliases) > 0 {
+		fmt.Printf("  aliases:   %s\n", strings.Join(plan.Aliases, ", "))
+	}
+	for _, use := range plan.ExternalUses {
+		fmt.Printf("  in use:    %s\n", use)
+	}
+	if plan.KeepConfig {
+		fmt.Println("  config:    kept, listed as removed and reinstallable")
+	}
+	if !plan.Allowed {
+		output.PrintWarning(plan.Reason)
+	}
+}
+
+// uninstallInteractive 列出已安装版本供用户选择，确认后依次卸载
+func uninstallInteractive(vm *version.VersionManager, opts version.UninstallOptions) error {
+	installed, err := vm.GetInstalledVersions()
+	if err != nil {
+		return fmt.Errorf("failed to get installed versions: %w", err)
+	}
+	current, _ := config.GetCurrentVersion()
+
+	var candidates []string
+	fmt.Println("Installed versions:")
+	for _, v := range installed {
+		if v == current {
+			fmt.Printf("   -  %s (active, protected)\n", v)
+			continue
+		}
+		candidates = append(candidates, v)
+		fmt.Printf("  %2d) %s\n", len(candidates), v)
+	}
+	if len(candidates) == 0 {
+		output.PrintInfo("Nothing to uninstall")
+		return nil
+	}
+
+	input := output.Prompt("Versions to remove (e.g. 1 3 or 2-4, empty to cancel)")
+	if input == "" {
+		output.PrintInfo("Aborted")
+		return nil
+	}
+	picked, err := parseSelection(input, len(candidates))
+	if err != nil {
+		return err
+	}
+	selected := make([]string, 0, len(picked))
+	for _, i := range picked {
+		selected = append(selected, candidates[i-1])
+	}
+	if !output.Confirm(fmt.Sprintf("Remove %s?", strings.Join(selected, ", "))) {
+		output.PrintInfo("Aborted")
+		return nil
+	}
+
+	failed := 0
+	for _, v := range selected {
+		if err := vm.UninstallVersionWithOptions(v, opts); err != nil {
+			failed++
+			output.PrintError(fmt.Sprintf("%s: %s", v, err.Error()))
+			continue
+		}
+		output.PrintSuccess(fmt.Sprintf("Uninstalled Go %s", v))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d versions could not be uninstalled", failed, len(selected))
+	}
+	return nil
+}
+
+// parseSelection 解析以空格或逗号分隔的编号与区间（如 "1 3"、"2-4"），返回 1..n 内去重排序后的编号
+func parseSelection(input string, n int) ([]int, error) {
+	seen := make(map[int]bool)
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' })
+	for _, field := range fields {
+		lo, hi, isRange := strings.Cut(field, "-")
+		if !isRange {
+			hi = lo
+		}
+		from, err1 := strconv.Atoi(lo)
+		to, err2 := strconv.Atoi(hi)
+		if err1 != nil || err2 != nil || from < 1 || to > n || from > to {
+			return nil, fmt.Errorf("invalid selection %q: expected numbers between 1 and %d", field, n)
+		}
+		for i := from; i <= to; i++ {
+			seen[i] = true
+		}
+	}
+	result := make([]int, 0, len(seen))
+	for i := range seen {
+		result = append(result, i)
+	}
+	sort.Ints(result)
+	return result, nil
+}
+
 func init() {
 	rootCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().Bool("force", false, "also remove named links and aliases that point to the version, and remove it even if it appears to be in use outside gvm")
+	uninstallCmd.Flags().Bool("keep-config", false, "remove the files but keep the version recorded for reinstalling")
+	uninstallCmd.Flags().BoolP("interactive", "i", false, "pick the versions to remove from a list")
+	uninstallCmd.Flags().Bool("dry-run", false, "show what would be removed without deleting anything")
+	uninstallCmd.Flags().Bool("json", false, "print the result as JSON (same as --output json)")
 }
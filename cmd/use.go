@@ -2,8 +2,12 @@ package cmd
 
 import (
     "fmt"
+    "os"
+    "path/filepath"
     "strings"
 
+    "github.com/philokun/gvm/internal/output"
+    "github.com/philokun/gvm/internal/utils"
     "github.com/philokun/gvm/internal/version"
     "github.com/spf13/cobra"
 )
@@ -14,30 +18,302 @@ var useCmd = &cobra.Command{
 	Short: "Switch to a specific Go version",
 	Long: `Switch to using a specific version of Go.
 	
-This command updates your PATH to use the specified Go version.`,
-	Args: cobra.ExactArgs(1),
+This command updates your PATH to use the specified Go version.
+
+With --json (or --output json) the human-readable messages are replaced by a
+single JSON object on stdout describing the result, and errors are printed as
+JSON to stderr:
+
+  {"version":"go1.21.5","goroot":"...","shim":"...","changed":true,"restart_shell":false}
+
+restart_shell is true when the shims directory is not yet on the PATH of the
+calling shell.
+
+Without a version, gvm looks for a .go-version file in the current directory
+and then in each parent directory, and switches to the version it names.
+
+--from-gomod <path> switches to the version declared in a go.mod, and
+--from-file <path> to the one in a .go-version file. The version file may
+contain 1.21.5, go1.21.5, v1.21.5 or a series such as 1.21 (the latest
+installed patch release); blank lines and lines starting with # are ignored.
+
+A number from 1 to 99 switches to that entry of 'gvm list --index' (the
+active version first, then the others from newest to oldest), e.g. 'gvm use 2'
+for the most recent version besides the active one. A named link with the
+same name takes precedence; Go versions always contain a dot (apart from go1,
+which has to be written in full), so numbers never clash with versions.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if versionFile(cmd) != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		// 不带版本时使用当前目录或上级目录中的 .go-version（--print-path 不带版本时使用当前版本）
+		return cobra.MaximumNArgs(1)(cmd, args)
+	},
+	ValidArgsFunction: completeInstalledVersions(true),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		versionStr := args[0]
+		if printPath, _ := cmd.Flags().GetBool("print-path"); printPath {
+			return printBinPath(cmd, args)
+		}
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		// --json 等价于 --output json：stdout 只输出结果对象，错误以 JSON 输出到 stderr
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			format = output.FormatJSON
+		}
+		jsonOut := format == output.FormatJSON
+		if jsonOut {
+			output.SetJSONErrors(true)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		// 只有 --json 时才需要额外的结果输出，其余情况与原先一样输出提示信息
+		quiet := flagQuiet || jsonOut
+
+		if len(args) == 0 && versionFile(cmd) == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			file, err := version.FindVersionFile(wd)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%w; pass a version such as 'gvm use 1.21.5' or pin one with 'echo 1.21.5 > %s'", err, version.VersionFileName)
+			}
+			// 与 --from-file 相同：解析版本并在切换记录中注明来源文件
+			if err := cmd.Flags().Set("from-file", file); err != nil {
+				return err
+			}
+		}
+
+		versionStr, err := resolveVersionArg(cmd, args, quiet, true)
+		if err != nil {
+			return err
+		}
+
+		vm := version.New()
+
+		// 切换来源写入 ~/.gvm/history.log
+		useOpts := version.UseOptions{Source: version.SwitchManual}
+		if file := versionFile(cmd); file != "" {
+			if abs, err := filepath.Abs(file); err == nil {
+				file = abs
+			}
+			useOpts = version.UseOptions{Source: version.SwitchDotfile, Detail: file}
+		}
+
+		// 命名链接可以作为版本别名使用；其次是 gvm alias 定义的别名与 list --index 的编号
+		if target, ok := vm.ResolveLink(versionStr); ok {
+			useOpts = version.UseOptions{Source: version.SwitchAlias, Detail: versionStr}
+			versionStr = target
+		} else if len(args) == 1 {
+			target, isAlias, err := resolveAlias(vm, versionStr, quiet)
+			if err != nil {
+				return err
+			}
+			if isAlias {
+				useOpts = version.UseOptions{Source: version.SwitchAlias, Detail: versionStr}
+				versionStr = target
+			}
+			target, isIndex, err := versionByIndex(vm, versionStr)
+			if err != nil {
+				return err
+			}
+			if isIndex {
+				if !quiet {
+					output.PrintInfo(fmt.Sprintf("#%s in 'gvm list --index' is Go %s", versionStr, target))
+				}
+				versionStr = target
+			}
+		}
 
 		// 标准化版本号格式
 		if !strings.HasPrefix(versionStr, "go") {
 			versionStr = "go" + versionStr
 		}
 
-		vm := version.New()
+		// 已是当前版本且 shim 指向正确时跳过所有写入（避免 cd 自动切换时反复改写 shell 配置）
+		if vm.IsActive(versionStr) {
+			if jsonOut {
+				return printUseResult(vm, versionStr, false)
+			}
+			if rehash, _ := cmd.Flags().GetBool("rehash"); rehash {
+				rehashGOBIN(versionStr)
+			}
+			if !flagQuiet {
+				fmt.Printf("Already using Go %s\n", versionStr)
+			}
+			return nil
+		}
 
-		fmt.Printf("Switching to Go %s...\n", versionStr)
+		if err := vm.CheckWritable(); err != nil {
+			return err
+		}
 
-		if err := vm.UseVersion(versionStr); err != nil {
+		// --quiet 时成功只静默切换（供 cd 自动切换等脚本调用），出错仍正常报告
+		if !quiet {
+			fmt.Printf("Switching to Go %s...\n", versionStr)
+		}
+
+		useOpts.NoHook, _ = cmd.Flags().GetBool("no-hook")
+		useOpts.HookToStderr = jsonOut
+		if err := vm.UseVersionWithOptions(versionStr, useOpts); err != nil {
 			return fmt.Errorf("failed to switch to version %s: %w", versionStr, err)
 		}
 
+		if jsonOut {
+			return printUseResult(vm, versionStr, true)
+		}
+		if rehash, _ := cmd.Flags().GetBool("rehash"); rehash {
+			rehashGOBIN(versionStr)
+		}
+		if flagQuiet {
+			return nil
+		}
+
         fmt.Printf("Now using Go %s\n", versionStr)
 
+		checkGOBIN(vm, versionStr)
+
+		// 首次使用时 shims 目录尚未进入当前终端的 PATH，提示如何生效
+		if shimsDir, err := utils.GetShimsDir(); err == nil && !utils.PathContains(shimsDir) {
+			output.PrintInfo(fmt.Sprintf("%s is not on your PATH yet; %s", shimsDir, utils.ActivationHint()))
+		}
+
 		return nil
 	},
 }
 
+// useResult 是 use --json 的输出
+type useResult struct {
+	Version      string `json:"version"`
+	GOROOT       string `json:"goroot"`
+	Shim         string `json:"shim"`
+	Changed      bool   `json:"changed"`       // false 表示已是当前版本，未做任何修改
+	RestartShell bool   `json:"restart_shell"` // shims 目录尚未进入当前终端的 PATH
+}
+
+// printUseResult 以 JSON 输出切换结果
+func printUseResult(vm *version.VersionManager, versionStr string, changed bool) error {
+	binPath, err := vm.GetBinPath(versionStr)
+	if err != nil {
+		return err
+	}
+	shimsDir, err := utils.GetShimsDir()
+	if err != nil {
+		return err
+	}
+	shim, err := utils.ShimPath("go")
+	if err != nil {
+		return err
+	}
+	return output.PrintJSON(useResult{
+		Version:      versionStr,
+		GOROOT:       filepath.Dir(binPath),
+		Shim:         shim,
+		Changed:      changed,
+		RestartShell: !utils.PathContains(shimsDir),
+	})
+}
+
+// printBinPath 只输出指定（或当前）版本的 bin 目录，不切换版本，便于 shell 集成：
+//
+//	export PATH="$(gvm use --print-path go1.21.6):$PATH"
+func printBinPath(cmd *cobra.Command, args []string) error {
+	// 出错时只向 stderr 输出错误信息，不附带用法说明
+	cmd.SilenceUsage = true
+	vm := version.New()
+
+	var versionStr string
+	if gomod, _ := cmd.Flags().GetString("from-gomod"); gomod != "" {
+		v, err := vm.ResolveGoMod(gomod, true)
+		if err != nil {
+			return err
+		}
+		versionStr = v
+	} else if file, _ := cmd.Flags().GetString("from-file"); file != "" {
+		v, err := vm.ResolveVersionFile(file, true)
+		if err != nil {
+			return err
+		}
+		versionStr = v
+	} else if len(args) == 1 {
+		// 与 gvm use 相同：命名链接、gvm alias 定义的别名与 list --index 的编号可以代替版本号；
+		// stdout 只输出路径，不提示解析结果
+		versionStr = args[0]
+		if target, ok := vm.ResolveLink(versionStr); ok {
+			versionStr = target
+		} else {
+			if target, ok, err := resolveAlias(vm, versionStr, true); err != nil {
+				return err
+			} else if ok {
+				versionStr = target
+			}
+			if target, ok, err := versionByIndex(vm, versionStr); err != nil {
+				return err
+			} else if ok {
+				versionStr = target
+			}
+		}
+	} else {
+		current, err := vm.GetCurrentVersion()
+		if err != nil {
+			return fmt.Errorf("failed to get current version: %w", err)
+		}
+		versionStr = current
+	}
+
+	// 标准化版本号格式
+	if versionStr != "system" && !strings.HasPrefix(versionStr, "go") {
+		versionStr = "go" + versionStr
+	}
+
+	binPath, err := vm.GetBinPath(versionStr)
+	if err != nil {
+		return err
+	}
+	fmt.Println(binPath)
+	return nil
+}
+
+// checkGOBIN 在 GOBIN 指向另一个版本的目录树时发出警告
+func checkGOBIN(vm *version.VersionManager, active string) {
+	gobin := version.GetGOBIN()
+	if owner := vm.GOBINConflict(gobin, active); owner != "" {
+		output.PrintWarning(fmt.Sprintf("GOBIN (%s) points inside the %s installation; tools installed with 'go install' will not follow version switches", gobin, owner))
+	}
+}
+
+// rehashGOBIN 列出 GOBIN 中由其他 Go 版本构建的工具，并给出重新安装命令
+func rehashGOBIN(active string) {
+	gobin := version.GetGOBIN()
+	tools, err := version.ListGOBINTools(gobin)
+	if err != nil {
+		output.PrintWarning(fmt.Sprintf("Failed to inspect GOBIN %s: %s", gobin, err.Error()))
+		return
+	}
+	fmt.Printf("GOBIN: %s\n", gobin)
+	stale := 0
+	for _, t := range tools {
+		if t.GoVersion == active || t.Path == "" {
+			continue
+		}
+		stale++
+		output.PrintWarning(fmt.Sprintf("%s was built with %s; rebuild with: go install %s@latest", t.Name, t.GoVersion, t.Path))
+	}
+	if stale == 0 {
+		output.PrintSuccess(fmt.Sprintf("All tools in GOBIN were built with %s", active))
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(useCmd)
+	useCmd.Flags().Bool("rehash", false, "report GOBIN tools built with a different Go version after switching")
+	useCmd.Flags().String("from-gomod", "", "switch to the version declared in the given go.mod")
+	useCmd.Flags().String("from-file", "", "switch to the version named in the given .go-version file")
+	useCmd.Flags().Bool("no-hook", false, "do not run the configured post-use hook")
+	useCmd.Flags().Bool("json", false, "print the result as JSON (same as --output json)")
+	useCmd.Flags().Bool("print-path", false, "print only the bin directory of the version (or the current one) without switching")
 }
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagWhichAll  bool
+	flagWhichJSON bool
+)
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which [version]",
+	Short: "Print the path of a version's go binary",
+	Long: `Print the absolute path of the go binary of an installed version, or of the
+active version when none is given. Names created with 'gvm link' are accepted,
+and 'system' prints the go found on PATH (as does an active version of system).
+
+With --all, print every installed version with its go binary, e.g. to
+register all toolchains with an IDE at once. Versions whose go binary is
+missing are reported on stderr and left out. --json (or --output json)
+prints the same mapping as a JSON object:
+
+  gvm which --all --json    # {"go1.21.5":"/home/me/.gvm/versions/go1.21.5/bin/go", ...}`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeInstalledVersions(true),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		vm := version.New()
+
+		format, err := outputFormat(output.FormatPlain)
+		if err != nil {
+			return err
+		}
+		// --json 等价于 --output json
+		if flagWhichJSON {
+			format = output.FormatJSON
+		}
+
+		if flagWhichAll {
+			if len(args) > 0 {
+				return fmt.Errorf("--all cannot be combined with a version argument")
+			}
+			return printAllBinaries(vm, format)
+		}
+
+		var versionStr string
+		if len(args) == 1 {
+			versionStr = args[0]
+			if target, ok := vm.ResolveLink(versionStr); ok {
+				versionStr = target
+			}
+			// system 表示 PATH 中不由 gvm 管理的 go
+			if versionStr != "system" {
+				versionStr = normalizeVersion(versionStr)
+			}
+		} else {
+			current, err := config.GetCurrentVersion()
+			if err != nil {
+				return err
+			}
+			if current == "" {
+				return fmt.Errorf("no version is active; run 'gvm use <version>' or name a version")
+			}
+			versionStr = current
+		}
+
+		path, err := vm.GoBinaryPath(versionStr)
+		if err != nil {
+			return err
+		}
+		return output.Render(format, whichEntry{Version: versionStr, Path: path}, nil, func() {
+			fmt.Println(path)
+		})
+	},
+}
+
+// whichEntry 是 which 命令的 JSON 输出结构
+type whichEntry struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+}
+
+// printAllBinaries 输出所有已安装版本的 go 二进制路径，缺少二进制的版本只在 stderr 警告
+func printAllBinaries(vm *version.VersionManager, format output.Format) error {
+	installed, err := vm.GetInstalledVersions()
+	if err != nil {
+		return fmt.Errorf("failed to get installed versions: %w", err)
+	}
+	paths := make(map[string]string, len(installed))
+	var found []string
+	for _, v := range installed {
+		path, err := vm.GoBinaryPath(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", v, err)
+			continue
+		}
+		paths[v] = path
+		found = append(found, v)
+	}
+
+	return output.Render(format, paths, func() {
+		output.PrintTableHeader("Version", "Path")
+		for _, v := range found {
+			output.PrintTableRow(v, paths[v])
+		}
+	}, func() {
+		for _, v := range found {
+			fmt.Printf("%s\t%s\n", v, paths[v])
+		}
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	whichCmd.Flags().BoolVar(&flagWhichAll, "all", false, "print the go binary of every installed version")
+	whichCmd.Flags().BoolVar(&flagWhichJSON, "json", false, "output as JSON (same as --output json)")
+}
@@ -2,7 +2,10 @@ module github.com/philokun/gvm
 
 go 1.25.3
 
-require github.com/spf13/cobra v1.10.1
+require (
+	github.com/spf13/cobra v1.10.1
+	golang.org/x/mod v0.40.0
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
@@ -5,36 +5,62 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/philokun/gvm/internal/utils"
 )
 
 type Config struct {
 	CurrentVersion string                 `json:"current_version"`
 	InstallDir     string                 `json:"install_dir"`
 	Versions       map[string]VersionInfo `json:"versions"`
+	Links          map[string]string      `json:"links,omitempty"`   // 命名 shim -> 版本
+	Aliases        map[string]string      `json:"aliases,omitempty"` // 版本别名 -> 版本，不创建 shim
+	Mirrors        []Mirror               `json:"mirrors,omitempty"`
+	Mirror         string                 `json:"mirror,omitempty"`               // 见 Settings
+	AltMirror      string                 `json:"alt_mirror,omitempty"`           // 见 Settings
+	Proxy          string                 `json:"proxy,omitempty"`                // 见 Settings
+	HTTPTimeout    string                 `json:"http_timeout,omitempty"`         // 见 Settings，例如 "45s"
+	Downloader     string                 `json:"downloader,omitempty"`           // 见 Settings
+	RedirectHosts  []string               `json:"redirect_hosts,omitempty"`       // 见 Settings
+	Source         string                 `json:"source,omitempty"`               // 见 Settings
+	PreferFiles    []string               `json:"prefer_files,omitempty"`         // 见 Settings
+	StableRoot     bool                   `json:"stable_root,omitempty"`          // 维护 ~/.gvm/go 指向当前版本
+	PostUseHook    string                 `json:"post_use_hook,omitempty"`        // 切换版本成功后执行的命令
+	HookStrict     bool                   `json:"post_use_hook_strict,omitempty"` // 钩子失败时切换也失败
+	NotifyUpdates  bool                   `json:"notify_updates,omitempty"`       // 每天检查一次 gvm 是否有新版本
 }
 
 type VersionInfo struct {
 	InstalledDate string `json:"installed_date"`
 	Active        bool   `json:"active"`
+	Unvalidated   bool   `json:"unvalidated,omitempty"` // 使用 --no-validate 安装，未校验 VERSION
+	Removed       bool   `json:"removed,omitempty"`     // 使用 uninstall --keep-config 删除了文件，可重新安装
 }
 
-var (
-	defaultConfig Config
-	configPath    string
-)
+// Mirror 是使用自定义 URL 模板的下载镜像
+type Mirror struct {
+	Name        string `json:"name"`
+	URLTemplate string `json:"url_template"` // 例如 https://example.com/{goversion}/go-{version}-{os}-{arch}.{ext}
+}
 
-func init() {
-	homeDir, _ := os.UserHomeDir()
-	configPath = filepath.Join(homeDir, ".gvm", "config.json")
-	defaultConfig = Config{
-		InstallDir: filepath.Join(homeDir, ".gvm", "versions"),
-		Versions:   make(map[string]VersionInfo),
-	}
+// configFile 返回 config.json 的路径；每次按当前主目录计算，使测试可以通过 HOME 隔离配置
+func configFile() string {
+	return filepath.Join(utils.HomeDir(), ".gvm", "config.json")
+}
+
+// DefaultInstallDir 返回默认安装目录 ~/.gvm/versions
+func DefaultInstallDir() string {
+	return filepath.Join(utils.HomeDir(), ".gvm", "versions")
 }
 
 func Load() (*Config, error) {
-	config := defaultConfig
+	config := Config{
+		InstallDir: DefaultInstallDir(),
+		Versions:   make(map[string]VersionInfo),
+	}
+	configPath := configFile()
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -56,6 +82,7 @@ func Load() (*Config, error) {
 }
 
 func Save(config *Config) error {
+	configPath := configFile()
 	// 确保配置目录存在
 	configDir := filepath.Dir(configPath)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
@@ -67,7 +94,13 @@ func Save(config *Config) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	// 先写临时文件再重命名，避免写入中断导致配置文件损坏
+	tmpPath := configPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -119,6 +152,22 @@ func AddVersion(version string) error {
 	return Save(config)
 }
 
+func SetVersionUnvalidated(version string, unvalidated bool) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	info, exists := config.Versions[version]
+	if !exists {
+		return fmt.Errorf("version %s is not recorded in config", version)
+	}
+	info.Unvalidated = unvalidated
+	config.Versions[version] = info
+
+	return Save(config)
+}
+
 func RemoveVersion(version string) error {
 	config, err := Load()
 	if err != nil {
@@ -135,6 +184,48 @@ func RemoveVersion(version string) error {
 	return Save(config)
 }
 
+// MarkVersionRemoved 保留版本记录但标记为文件已删除（uninstall --keep-config）
+func MarkVersionRemoved(version string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	info := config.Versions[version]
+	info.Active = false
+	info.Unvalidated = false
+	info.Removed = true
+	config.Versions[version] = info
+
+	if config.CurrentVersion == version {
+		config.CurrentVersion = ""
+	}
+
+	return Save(config)
+}
+
+// RemoveVersions 在一次保存中删除多个版本的记录
+func RemoveVersions(versions []string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		delete(config.Versions, version)
+		if config.CurrentVersion == version {
+			config.CurrentVersion = ""
+		}
+		for name, v := range config.Links {
+			if v == version {
+				delete(config.Links, name)
+			}
+		}
+	}
+
+	return Save(config)
+}
+
 func GetInstallDir() (string, error) {
 	config, err := Load()
 	if err != nil {
@@ -142,3 +233,196 @@ func GetInstallDir() (string, error) {
 	}
 	return config.InstallDir, nil
 }
+
+// ResolveInstallDir 返回安装 Go 版本的目录：环境变量 GVM_HOME 优先，其次是 config.json 的 install_dir，
+// 最后是 ~/.gvm/versions
+func ResolveInstallDir() string {
+	if dir := strings.TrimSpace(os.Getenv("GVM_HOME")); dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			return abs
+		}
+		return dir
+	}
+	if dir, err := GetInstallDir(); err == nil && strings.TrimSpace(dir) != "" {
+		return dir
+	}
+	return DefaultInstallDir()
+}
+
+// SetInstallDir 将安装目录写入 config.json
+func SetInstallDir(dir string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	config.InstallDir = dir
+
+	return Save(config)
+}
+
+func GetLinks() (map[string]string, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if config.Links == nil {
+		return map[string]string{}, nil
+	}
+	return config.Links, nil
+}
+
+func AddLink(name, version string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	if config.Links == nil {
+		config.Links = make(map[string]string)
+	}
+	config.Links[name] = version
+
+	return Save(config)
+}
+
+func RemoveLink(name string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	delete(config.Links, name)
+
+	return Save(config)
+}
+
+// GetAliases 返回 gvm alias 定义的版本别名
+func GetAliases() (map[string]string, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if config.Aliases == nil {
+		return map[string]string{}, nil
+	}
+	return config.Aliases, nil
+}
+
+// SetAlias 将别名 name 指向 version，已存在时覆盖
+func SetAlias(name, version string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	if config.Aliases == nil {
+		config.Aliases = make(map[string]string)
+	}
+	config.Aliases[name] = version
+
+	return Save(config)
+}
+
+// RemoveAlias 删除别名 name；别名不存在时返回错误
+func RemoveAlias(name string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := config.Aliases[name]; !ok {
+		return fmt.Errorf("alias %s does not exist", name)
+	}
+	delete(config.Aliases, name)
+
+	return Save(config)
+}
+
+// ResolveAlias 返回别名 name 指向的版本；不是别名时返回 false
+func ResolveAlias(name string) (string, bool) {
+	aliases, err := GetAliases()
+	if err != nil {
+		return "", false
+	}
+	v, ok := aliases[name]
+	return v, ok
+}
+
+func GetStableRoot() (bool, error) {
+	config, err := Load()
+	if err != nil {
+		return false, err
+	}
+	return config.StableRoot, nil
+}
+
+func SetStableRoot(enabled bool) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	config.StableRoot = enabled
+
+	return Save(config)
+}
+
+// GetPostUseHook 返回切换版本后执行的命令，以及钩子失败时是否令切换失败
+func GetPostUseHook() (string, bool, error) {
+	config, err := Load()
+	if err != nil {
+		return "", false, err
+	}
+	return config.PostUseHook, config.HookStrict, nil
+}
+
+// SetPostUseHook 设置切换版本后执行的命令，为空时删除钩子
+func SetPostUseHook(command string) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	config.PostUseHook = command
+
+	return Save(config)
+}
+
+func SetPostUseHookStrict(strict bool) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	config.HookStrict = strict
+
+	return Save(config)
+}
+
+func GetNotifyUpdates() (bool, error) {
+	config, err := Load()
+	if err != nil {
+		return false, err
+	}
+	return config.NotifyUpdates, nil
+}
+
+func SetNotifyUpdates(enabled bool) error {
+	config, err := Load()
+	if err != nil {
+		return err
+	}
+
+	config.NotifyUpdates = enabled
+
+	return Save(config)
+}
+
+func GetMirrors() ([]Mirror, error) {
+	config, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return config.Mirrors, nil
+}
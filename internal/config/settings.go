@@ -0,0 +1,184 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// 设置项的默认值
+const (
+	DefaultMirror      = "https://go.dev"
+	DefaultAltMirror   = "https://golang.google.cn"
+	DefaultHTTPTimeout = 30 * time.Second
+	DefaultDownloader  = "builtin"
+)
+
+// 安装来源（GVM_SOURCE）
+const (
+	SourceGoDev   = "go.dev"  // go.dev 风格镜像上的 .tar.gz/.zip 安装包（默认）
+	SourceGoProxy = "goproxy" // GOPROXY 上以 golang.org/toolchain 模块发布的工具链
+)
+
+// 校验值来源（GVM_VERIFY_FROM_OFFICIAL）
+const (
+	VerifyOfficialOff    = ""       // 使用版本列表（可能来自镜像）中的校验值（默认）
+	VerifyOfficialStrict = "strict" // 校验值总是取自 go.dev，无法获取时安装失败（GVM_VERIFY_FROM_OFFICIAL=1）
+	VerifyOfficialWarn   = "warn"   // 校验值优先取自 go.dev，无法获取时警告并使用镜像提供的值
+)
+
+// Settings 是解析后的网络与下载设置，由 version 包与下载客户端共同使用。
+//
+// 每一项按以下优先级解析（高到低）：
+//  1. 环境变量：GVM_DL_MIRROR、GVM_ALT_MIRROR、GVM_PROXY、GVM_HTTP_TIMEOUT、GVM_DOWNLOADER、
+//     GVM_REDIRECT_HOSTS（逗号分隔）、GVM_SOURCE、GVM_PREFER_FILES（逗号分隔）
+//  2. 命令行标志（通过 OverrideSettings 设置，例如 --mirror）
+//  3. config.json：mirror、alt_mirror、proxy、http_timeout、downloader、redirect_hosts、source、prefer_files
+//  4. 默认值
+type Settings struct {
+	Mirror      string        // go.dev 风格的下载与版本 JSON 基址
+	AltMirror   string        // 先于 Mirror 尝试的 go.dev 风格镜像（默认中国镜像），为空时只使用 Mirror
+	Proxy       string        // HTTP(S) 代理地址，为空时使用 HTTPS_PROXY 等标准环境变量
+	HTTPTimeout time.Duration // 版本列表、校验值等元数据请求的超时时间（不限制安装包下载）
+	Downloader  string        // 下载工具：builtin、aria2 或 curl
+	Source      string        // 安装来源：go.dev 或 goproxy
+
+	VerifyOfficial string // 校验值来源：VerifyOfficialOff、VerifyOfficialStrict 或 VerifyOfficialWarn，只能通过环境变量设置
+
+	RedirectHosts []string // 允许重定向到的主机（含子域名），为空时不限制
+	PreferFiles   []string // 同一平台有多个安装包时优先选择文件名包含其中子串的（越靠前越优先）
+
+	// HeaderHosts 是可以收到 GVM_HTTP_HEADERS 的主机：取自 GVM_HTTP_HEADERS_HOSTS（逗号分隔），
+	// 未设置时只有 Mirror 的主机；优先镜像、go.dev、GitHub 等其他主机收不到这些请求头
+	HeaderHosts []string
+}
+
+// flagOverrides 保存命令行标志设置的值，零值表示未设置
+var flagOverrides Settings
+
+// OverrideSettings 记录命令行标志设置的值，其中的非零字段优先于 config.json 与默认值，但不覆盖环境变量
+func OverrideSettings(o Settings) {
+	if o.Mirror != "" {
+		flagOverrides.Mirror = o.Mirror
+	}
+	if o.AltMirror != "" {
+		flagOverrides.AltMirror = o.AltMirror
+	}
+	if o.Proxy != "" {
+		flagOverrides.Proxy = o.Proxy
+	}
+	if o.HTTPTimeout > 0 {
+		flagOverrides.HTTPTimeout = o.HTTPTimeout
+	}
+	if o.Downloader != "" {
+		flagOverrides.Downloader = o.Downloader
+	}
+	if o.Source != "" {
+		flagOverrides.Source = o.Source
+	}
+	if len(o.RedirectHosts) > 0 {
+		flagOverrides.RedirectHosts = o.RedirectHosts
+	}
+	if len(o.PreferFiles) > 0 {
+		flagOverrides.PreferFiles = o.PreferFiles
+	}
+}
+
+// ResolveSettings 按优先级解析当前生效的设置；无法读取 config.json 时忽略该来源
+func ResolveSettings() Settings {
+	var file Config
+	if cfg, err := Load(); err == nil {
+		file = *cfg
+	}
+
+	s := Settings{
+		Mirror:     firstNonEmpty(os.Getenv("GVM_DL_MIRROR"), flagOverrides.Mirror, file.Mirror, DefaultMirror),
+		Proxy:      firstNonEmpty(os.Getenv("GVM_PROXY"), flagOverrides.Proxy, file.Proxy),
+		Downloader: strings.ToLower(firstNonEmpty(os.Getenv("GVM_DOWNLOADER"), flagOverrides.Downloader, file.Downloader, DefaultDownloader)),
+		Source:     strings.ToLower(firstNonEmpty(os.Getenv("GVM_SOURCE"), flagOverrides.Source, file.Source, SourceGoDev)),
+	}
+	s.Mirror = strings.TrimRight(s.Mirror, "/")
+	// off 关闭优先镜像，例如 go.dev 访问更快或镜像被拦截的网络
+	s.AltMirror = strings.TrimRight(firstNonEmpty(os.Getenv("GVM_ALT_MIRROR"), flagOverrides.AltMirror, file.AltMirror, DefaultAltMirror), "/")
+	if strings.EqualFold(s.AltMirror, "off") {
+		s.AltMirror = ""
+	}
+	s.VerifyOfficial = parseVerifyOfficial(os.Getenv("GVM_VERIFY_FROM_OFFICIAL"))
+
+	s.RedirectHosts = splitList(os.Getenv("GVM_REDIRECT_HOSTS"))
+	if len(s.RedirectHosts) == 0 {
+		s.RedirectHosts = flagOverrides.RedirectHosts
+	}
+	if len(s.RedirectHosts) == 0 {
+		s.RedirectHosts = file.RedirectHosts
+	}
+
+	s.PreferFiles = splitList(os.Getenv("GVM_PREFER_FILES"))
+	if len(s.PreferFiles) == 0 {
+		s.PreferFiles = flagOverrides.PreferFiles
+	}
+	if len(s.PreferFiles) == 0 {
+		s.PreferFiles = file.PreferFiles
+	}
+
+	s.HeaderHosts = splitList(os.Getenv("GVM_HTTP_HEADERS_HOSTS"))
+	if len(s.HeaderHosts) == 0 {
+		if u, err := url.Parse(s.Mirror); err == nil && u.Host != "" {
+			s.HeaderHosts = []string{u.Host}
+		}
+	}
+
+	s.HTTPTimeout = parseTimeout(os.Getenv("GVM_HTTP_TIMEOUT"))
+	if s.HTTPTimeout <= 0 {
+		s.HTTPTimeout = flagOverrides.HTTPTimeout
+	}
+	if s.HTTPTimeout <= 0 {
+		s.HTTPTimeout = parseTimeout(file.HTTPTimeout)
+	}
+	if s.HTTPTimeout <= 0 {
+		s.HTTPTimeout = DefaultHTTPTimeout
+	}
+	return s
+}
+
+// firstNonEmpty 返回第一个去除空白后非空的值
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// splitList 解析逗号分隔的列表，忽略空项
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTimeout 解析超时设置，无效或非正的值返回 0
+func parseTimeout(v string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// parseVerifyOfficial 解析 GVM_VERIFY_FROM_OFFICIAL：1、true、strict 为严格模式，warn 为仅警告，其余值关闭
+func parseVerifyOfficial(v string) string {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "yes", VerifyOfficialStrict:
+		return VerifyOfficialStrict
+	case VerifyOfficialWarn:
+		return VerifyOfficialWarn
+	}
+	return VerifyOfficialOff
+}
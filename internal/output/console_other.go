@@ -0,0 +1,8 @@
+//go:build !windows
+
+package output
+
+// consoleUTF8 只在 Windows 上检查控制台代码页，其他系统由 locale 决定
+func consoleUTF8() bool {
+	return false
+}
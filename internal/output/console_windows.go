@@ -0,0 +1,17 @@
+package output
+
+import "syscall"
+
+// cpUTF8 是 UTF-8 的代码页编号
+const cpUTF8 = 65001
+
+var procGetConsoleOutputCP = syscall.NewLazyDLL("kernel32.dll").NewProc("GetConsoleOutputCP")
+
+// consoleUTF8 判断控制台的输出代码页是否为 UTF-8（chcp 65001）；Windows Terminal 总是支持 UTF-8
+func consoleUTF8() bool {
+	if _, ok := syscall.Getenv("WT_SESSION"); ok {
+		return true
+	}
+	cp, _, _ := procGetConsoleOutputCP.Call()
+	return cp == cpUTF8
+}
@@ -1,13 +1,15 @@
 package output
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
-// Colors 定义终端颜色常量
-const (
+// Colors 定义终端颜色；ASCII 模式（见 SetASCII）下均为空字符串
+var (
 	ColorReset  = "\033[0m"
 	ColorRed    = "\033[31m"
 	ColorGreen  = "\033[32m"
@@ -18,29 +20,205 @@ const (
 	ColorWhite  = "\033[37m"
 )
 
+// 消息前缀符号；ASCII 模式（或终端不支持 UTF-8，见 SetASCIISymbols）下替换为纯 ASCII 字符，
+// 便于在旧版 Windows 控制台与没有 UTF-8 locale 的环境中显示
+var (
+	SymbolSuccess  = "✓"
+	SymbolError    = "✗"
+	SymbolWarning  = "⚠"
+	SymbolInfo     = "ℹ"
+	SymbolProgress = "⟳"
+	SymbolVerbose  = "»"
+	SymbolArrow    = "→"
+)
+
+// colorVars 与 symbolVars 是 ASCII 模式下需要替换的颜色与符号，asciiSymbols 为符号对应的替换值
+var (
+	colorVars = []*string{
+		&ColorReset, &ColorRed, &ColorGreen, &ColorYellow, &ColorBlue, &ColorPurple, &ColorCyan, &ColorWhite,
+	}
+	symbolVars = []*string{
+		&SymbolSuccess, &SymbolError, &SymbolWarning, &SymbolInfo, &SymbolProgress, &SymbolVerbose, &SymbolArrow,
+	}
+	asciiSymbols = []string{"[OK]", "[ERR]", "[WARN]", "[i]", "[..]", ">>", "->"}
+
+	defaultColors  = currentValues(colorVars)
+	defaultSymbols = currentValues(symbolVars)
+
+	spinnerFrames        = defaultSpinnerFrames
+	defaultSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	asciiSpinnerFrames   = []string{"|", "/", "-", "\\"}
+)
+
+func currentValues(vars []*string) []string {
+	values := make([]string, len(vars))
+	for i, v := range vars {
+		values[i] = *v
+	}
+	return values
+}
+
+// ascii 为 true 时只输出 ASCII 字符且不使用颜色
+var ascii bool
+
+// SetASCII 开启或关闭 ASCII 模式：关闭颜色，并将符号替换为纯 ASCII 字符
+func SetASCII(enabled bool) {
+	ascii = enabled
+	colors := defaultColors
+	if enabled {
+		colors = make([]string, len(colorVars))
+	}
+	for i, v := range colorVars {
+		*v = colors[i]
+	}
+	SetASCIISymbols(enabled)
+}
+
+// SetASCIISymbols 只将符号与 spinner 替换为纯 ASCII 字符（[OK]、[ERR]、[WARN]、[i] 与 |/-\），保留颜色，
+// 用于支持颜色但不支持 UTF-8 的终端
+func SetASCIISymbols(enabled bool) {
+	symbols, frames := defaultSymbols, defaultSpinnerFrames
+	if enabled {
+		symbols, frames = asciiSymbols, asciiSpinnerFrames
+	}
+	for i, v := range symbolVars {
+		*v = symbols[i]
+	}
+	spinnerFrames = frames
+}
+
+// ASCII 返回是否处于 ASCII 模式
+func ASCII() bool {
+	return ascii
+}
+
+// ASCIIFromEnv 解析 GVM_ASCII：返回其布尔值，以及是否设置了有效的值
+func ASCIIFromEnv() (enabled, ok bool) {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("GVM_ASCII")))
+	return enabled, err == nil
+}
+
+// SupportsUTF8 判断终端能否显示 UTF-8 符号：Windows 上检查控制台的输出代码页，
+// 其他系统检查 LC_ALL、LC_CTYPE、LANG 中第一个非空的 locale
+func SupportsUTF8() bool {
+	if consoleUTF8() {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return localeIsUTF8(locale)
+		}
+	}
+	return false
+}
+
+// localeIsUTF8 判断 locale（如 en_US.UTF-8、C.utf8）的字符集是否为 UTF-8
+func localeIsUTF8(locale string) bool {
+	locale = strings.ToLower(locale)
+	if i := strings.IndexByte(locale, '@'); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.HasSuffix(locale, ".utf-8") || strings.HasSuffix(locale, ".utf8")
+}
+
+// NoColor 判断是否设置了 NO_COLOR（https://no-color.org）
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
 // PrintSuccess 打印成功消息
 func PrintSuccess(message string) {
-	fmt.Printf("%s✓%s %s\n", ColorGreen, ColorReset, message)
+	fmt.Printf("%s%s%s %s\n", ColorGreen, SymbolSuccess, ColorReset, message)
+}
+
+// jsonErrors 为 true 时错误以 JSON 形式输出，PrintError 的人类可读输出被抑制
+var jsonErrors bool
+
+// SetJSONErrors 开启或关闭 JSON 错误输出模式
+func SetJSONErrors(enabled bool) {
+	jsonErrors = enabled
+}
+
+// JSONErrors 返回是否处于 JSON 错误输出模式
+func JSONErrors() bool {
+	return jsonErrors
+}
+
+// PrintErrorJSON 以 {"error":...,"code":...} 形式向 stderr 输出错误
+func PrintErrorJSON(message, code string) {
+	data, _ := json.Marshal(struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}{message, code})
+	fmt.Fprintln(os.Stderr, string(data))
+}
+
+// verbose 为 true 时输出 PrintVerbose 的诊断信息
+var verbose bool
+
+// SetVerbose 开启或关闭详细输出
+func SetVerbose(enabled bool) {
+	verbose = enabled
+}
+
+// Verbose 返回是否开启了详细输出
+func Verbose() bool {
+	return verbose
+}
+
+// progress 为 false 时不输出下载进度，只保留 Downloading/Extracting 等阶段信息
+var progress = true
+
+// SetProgress 开启或关闭下载进度显示
+func SetProgress(enabled bool) {
+	progress = enabled
+}
+
+// Progress 返回是否显示下载进度
+func Progress() bool {
+	return progress
+}
+
+// IsCI 判断是否运行在 CI 环境中（CI=true，GitHub Actions、GitLab CI 等都会设置）
+func IsCI() bool {
+	ci, err := strconv.ParseBool(os.Getenv("CI"))
+	return err == nil && ci
+}
+
+// PrintVerbose 在开启 --verbose 时向 stderr 输出诊断信息
+func PrintVerbose(message string) {
+	if !verbose {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s%s%s %s\n", ColorCyan, SymbolVerbose, ColorReset, message)
 }
 
 // PrintError 打印错误消息
 func PrintError(message string) {
-	fmt.Fprintf(os.Stderr, "%s✗%s %s\n", ColorRed, ColorReset, message)
+	if jsonErrors {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s%s%s %s\n", ColorRed, SymbolError, ColorReset, message)
 }
 
 // PrintWarning 打印警告消息
 func PrintWarning(message string) {
-	fmt.Printf("%s⚠%s %s\n", ColorYellow, ColorReset, message)
+	fmt.Printf("%s%s%s %s\n", ColorYellow, SymbolWarning, ColorReset, message)
 }
 
 // PrintInfo 打印信息消息
 func PrintInfo(message string) {
-	fmt.Printf("%sℹ%s %s\n", ColorBlue, ColorReset, message)
+	fmt.Printf("%s%s%s %s\n", ColorBlue, SymbolInfo, ColorReset, message)
+}
+
+// PrintNotice 向 stderr 打印不属于命令输出的提示（例如 gvm 有新版本），不影响 stdout 的解析
+func PrintNotice(message string) {
+	fmt.Fprintf(os.Stderr, "%s%s%s %s\n", ColorBlue, SymbolInfo, ColorReset, message)
 }
 
 // PrintProgress 打印进度消息
 func PrintProgress(message string) {
-	fmt.Printf("%s⟳%s %s\n", ColorCyan, ColorReset, message)
+	fmt.Printf("%s%s%s %s\n", ColorCyan, SymbolProgress, ColorReset, message)
 }
 
 // PrintHeader 打印标题
@@ -85,16 +263,58 @@ func Confirm(prompt string) bool {
 	return response == "y" || response == "yes"
 }
 
+// IsTerminal 判断标准输入是否为终端，非终端时不应进行交互式询问
+func IsTerminal() bool {
+	return isTerminal(os.Stdin)
+}
+
+// StdoutIsTerminal 判断标准输出是否为终端，非终端（管道、CI 日志）时不应使用 \r 原地刷新
+func StdoutIsTerminal() bool {
+	return isTerminal(os.Stdout)
+}
+
+// StderrIsTerminal 判断标准错误是否为终端
+func StderrIsTerminal() bool {
+	return isTerminal(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null 也是字符设备
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+// Prompt 输出提示并读取一行输入（不含换行符）
+func Prompt(prompt string) string {
+	fmt.Printf("%s?%s %s: ", ColorYellow, ColorReset, prompt)
+
+	// 逐字节读取，不预读后续输入，之后的 Confirm 仍能读到下一行
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n == 0 || err != nil || buf[0] == '\n' {
+			break
+		}
+		line = append(line, buf[0])
+	}
+	return strings.TrimSpace(string(line))
+}
+
 // Spinner 显示加载动画
 func Spinner(message string) func() {
 	done := make(chan bool)
 	go func() {
-		spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+		spinner := spinnerFrames
 		i := 0
 		for {
 			select {
 			case <-done:
-				fmt.Printf("\r%s%s%s\n", ColorGreen, "✓", ColorReset)
+				fmt.Printf("\r%s%s%s\n", ColorGreen, SymbolSuccess, ColorReset)
 				return
 			default:
 				fmt.Printf("\r%s%s%s %s", ColorCyan, spinner[i%len(spinner)], ColorReset, message)
@@ -107,3 +327,52 @@ func Spinner(message string) func() {
 		done <- true
 	}
 }
+
+// Format 表示命令输出格式
+type Format string
+
+// 支持的输出格式
+const (
+	FormatTable Format = "table"
+	FormatPlain Format = "plain"
+	FormatJSON  Format = "json"
+)
+
+// ParseFormat 解析输出格式字符串，空字符串返回默认格式
+func ParseFormat(s string, def Format) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "":
+		return def, nil
+	case FormatTable:
+		return FormatTable, nil
+	case FormatPlain:
+		return FormatPlain, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q (expected table, plain or json)", s)
+	}
+}
+
+// PrintJSON 以缩进 JSON 形式输出数据
+func PrintJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Render 按输出格式渲染数据：json 直接序列化 data，table/plain 调用对应的渲染函数，
+// 缺少某种渲染函数时使用另一种
+func Render(format Format, data interface{}, renderTable, renderPlain func()) error {
+	if format == FormatJSON {
+		return PrintJSON(data)
+	}
+	if format == FormatPlain && renderPlain != nil || renderTable == nil {
+		if renderPlain != nil {
+			renderPlain()
+		}
+		return nil
+	}
+	renderTable()
+	return nil
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 安装包格式
+const (
+	FormatTarGz  = "tar.gz"
+	FormatTarBz2 = "tar.bz2"
+	FormatZip    = "zip"
+	FormatXz     = "xz"
+)
+
+// 各压缩格式的魔数
+var archiveMagics = []struct {
+	format string
+	magic  []byte
+}{
+	{FormatTarGz, []byte{0x1f, 0x8b}},
+	{FormatTarBz2, []byte("BZh")},
+	{FormatZip, []byte("PK\x03\x04")},
+	{FormatXz, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// DetectArchiveFormat 通过文件头部的魔数识别安装包格式，无法识别时返回空字符串
+func DetectArchiveFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	header := make([]byte, 8)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read archive header: %w", err)
+	}
+	header = header[:n]
+	for _, m := range archiveMagics {
+		if bytes.HasPrefix(header, m.magic) {
+			return m.format, nil
+		}
+	}
+	return "", nil
+}
+
+// ArchiveFormatFromName 根据文件名后缀推断安装包格式，无法识别时返回空字符串
+func ArchiveFormatFromName(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGz
+	case strings.HasSuffix(name, ".tar.bz2"):
+		return FormatTarBz2
+	case strings.HasSuffix(name, ".zip"):
+		return FormatZip
+	case strings.HasSuffix(name, ".tar.xz"):
+		return FormatXz
+	}
+	return ""
+}
+
+// ExtractOptions 控制 ExtractArchiveWithOptions 的行为
+type ExtractOptions struct {
+	Exclude     []string // 不解压的目录，相对于去除顶层前缀后的路径，例如 "src"
+	StripPrefix string   // 要去除的顶层前缀，为空时为 "go/"
+}
+
+// trim 去除条目名称的顶层前缀
+func (o ExtractOptions) trim(name string) string {
+	prefix := o.StripPrefix
+	if prefix == "" {
+		prefix = "go/"
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+// excluded 判断条目 name 是否位于被排除的目录中
+func (o ExtractOptions) excluded(name string) bool {
+	name = strings.TrimSuffix(filepath.ToSlash(name), "/")
+	for _, dir := range o.Exclude {
+		if name == dir || strings.HasPrefix(name, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractArchive 按内容识别安装包格式并解压，无法从内容识别时回退到文件名后缀
+func ExtractArchive(archivePath, filename, destPath string) error {
+	return ExtractArchiveWithOptions(archivePath, filename, destPath, ExtractOptions{})
+}
+
+// ExtractArchiveWithOptions 按给定选项解压安装包。压缩流损坏或被截断时返回 *CorruptArchiveError。
+func ExtractArchiveWithOptions(archivePath, filename, destPath string, opts ExtractOptions) error {
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = ArchiveFormatFromName(filename)
+	}
+
+	switch format {
+	case FormatTarGz:
+		return corruptArchive(archivePath, extractTarGz(archivePath, destPath, opts))
+	case FormatTarBz2:
+		return corruptArchive(archivePath, extractTarBz2(archivePath, destPath, opts))
+	case FormatZip:
+		return corruptArchive(archivePath, extractZip(archivePath, destPath, opts))
+	case FormatXz:
+		return fmt.Errorf("xz-compressed archives are not supported: %s", filename)
+	default:
+		return fmt.Errorf("unsupported package format: %s", filename)
+	}
+}
+
+// CorruptArchiveError 表示安装包的压缩数据损坏或不完整。没有校验值可用时，
+// 被截断的下载只能在解压时发现，底层的 "unexpected EOF" 等错误难以理解。
+type CorruptArchiveError struct {
+	Path string
+	Size int64
+	Err  error
+}
+
+func (e *CorruptArchiveError) Error() string {
+	return fmt.Sprintf("the downloaded archive appears corrupt or truncated (%s, %d bytes): %v; try re-running install, possibly with a different --mirror",
+		e.Path, e.Size, e.Err)
+}
+
+func (e *CorruptArchiveError) Unwrap() error {
+	return e.Err
+}
+
+// IsCorruptArchive 判断错误链中是否包含 *CorruptArchiveError
+func IsCorruptArchive(err error) bool {
+	var ce *CorruptArchiveError
+	return errors.As(err, &ce)
+}
+
+// corruptArchive 在 err 来自损坏或截断的压缩数据时将其包装为 *CorruptArchiveError，其他错误原样返回
+func corruptArchive(archivePath string, err error) error {
+	if err == nil || !isCorruptData(err) {
+		return err
+	}
+	var size int64
+	if fi, statErr := os.Stat(archivePath); statErr == nil {
+		size = fi.Size()
+	}
+	return &CorruptArchiveError{Path: archivePath, Size: size, Err: err}
+}
+
+// isCorruptData 判断 err 是否由压缩数据本身的问题引起（而不是磁盘写入失败等）
+func isCorruptData(err error) bool {
+	var flateErr flate.CorruptInputError
+	var bzip2Err bzip2.StructuralError
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, gzip.ErrHeader) ||
+		errors.Is(err, gzip.ErrChecksum) ||
+		errors.Is(err, zip.ErrFormat) ||
+		errors.Is(err, zip.ErrChecksum) ||
+		errors.Is(err, tar.ErrHeader) ||
+		errors.As(err, &flateErr) ||
+		errors.As(err, &bzip2Err)
+}
+
+// ZipHash1 计算 zip 文件内容的 h1: 摘要（与 go.sum 及校验和数据库中模块 zip 的摘要算法相同）：
+// 对按名称排序的每个文件写入 "<sha256>  <name>\n"，再对整体取 SHA256 并 base64 编码。
+func ZipHash1(zipPath string) (string, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer r.Close()
+
+	files := make([]*zip.File, 0, len(r.File))
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if strings.Contains(f.Name, "\n") {
+			return "", fmt.Errorf("invalid file name %q in zip", f.Name)
+		}
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	summary := sha256.New()
+	for _, f := range files {
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", h.Sum(nil), f.Name)
+	}
+	return "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil)), nil
+}
@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/output"
+)
+
+// 外部下载工具名称，通过 GVM_DOWNLOADER 或 config.json 的 downloader 选择
+const (
+	DownloaderBuiltin = "builtin"
+	DownloaderAria2   = "aria2"
+	DownloaderCurl    = "curl"
+)
+
+// ParseDownloader 将下载工具名称规范化为 DownloaderBuiltin、DownloaderAria2 或 DownloaderCurl，
+// 无法识别的名称视为内置下载器
+func ParseDownloader(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "aria2", "aria2c":
+		return DownloaderAria2
+	case "curl":
+		return DownloaderCurl
+	default:
+		return DownloaderBuiltin
+	}
+}
+
+// downloadWithExternal 使用外部工具下载文件。
+// 返回 handled=false 表示未配置外部工具或工具不存在，调用方应回退到内置下载器。
+//
+// 外部工具自行跟随重定向，因此配置了 RedirectHosts 时：curl 只下载内置客户端按允许的主机解析出的
+// 最终地址且不再跟随重定向；aria2c 没有限制或关闭重定向的选项，改用内置下载器。
+// 要发送 GVM_HTTP_HEADERS 时同样先解析出最终地址，只有它属于 SetHeaderHosts 允许的主机时才交给外部工具。
+func downloadWithExternal(url, destPath string, opts DownloadOptions) (handled bool, err error) {
+	downloader := ParseDownloader(opts.Downloader)
+	var name string
+	switch downloader {
+	case DownloaderAria2:
+		name = "aria2c"
+	case DownloaderCurl:
+		name = "curl"
+	default:
+		return false, nil
+	}
+	bin, lookErr := exec.LookPath(name)
+	if lookErr != nil {
+		return false, nil
+	}
+
+	followRedirects := true
+	if len(opts.RedirectHosts) > 0 || len(headerLines(url)) > 0 {
+		if downloader == DownloaderAria2 && len(opts.RedirectHosts) > 0 {
+			output.PrintWarning("aria2c cannot restrict redirects to redirect_hosts; using the built-in downloader")
+			return false, nil
+		}
+		final, err := resolveRedirects(url, opts)
+		if err != nil {
+			return true, err
+		}
+		url, followRedirects = final, false
+	}
+
+	if err := EnsureDir(filepath.Dir(destPath)); err != nil {
+		return true, fmt.Errorf("failed to ensure download dir: %w", err)
+	}
+
+	// GVM_HTTP_HEADERS 中的令牌不出现在命令行（其他用户可通过 ps 看到），
+	// 而是写入只有当前用户可读的临时文件
+	headers := headerLines(url)
+	var cmd *exec.Cmd
+	switch downloader {
+	case DownloaderAria2:
+		// 断点续传 + 多连接；aria2c 按自身内置的上限跟随重定向，没有调整次数的选项
+		args := []string{
+			"--continue=true",
+			"--max-connection-per-server=8",
+			"--split=8",
+			"--min-split-size=1M",
+			"--allow-overwrite=true",
+			"--auto-file-renaming=false",
+			"--user-agent=" + UserAgent(),
+			"--dir", filepath.Dir(destPath),
+			"--out", filepath.Base(destPath),
+		}
+		if opts.Proxy != "" {
+			args = append(args, "--all-proxy="+opts.Proxy)
+		}
+		if !output.Progress() {
+			args = append(args, "--show-console-readout=false", "--summary-interval=0", "--console-log-level=warn")
+		}
+		// 输入文件中的 URL 之后缩进的行是该下载的选项
+		lines := []string{url}
+		for _, h := range headers {
+			lines = append(lines, "  header="+h)
+		}
+		inputFile, err := writePrivateFile(lines)
+		if err != nil {
+			return true, err
+		}
+		defer os.Remove(inputFile)
+		cmd = exec.Command(bin, append(args, "--input-file", inputFile)...)
+	case DownloaderCurl:
+		// 失败返回非零、断点续传、自动重试
+		args := []string{
+			"--fail",
+			"--continue-at", "-",
+			"--retry", "3",
+			"--user-agent", UserAgent(),
+			"--output", destPath,
+		}
+		if followRedirects {
+			args = append(args, "--location", "--max-redirs", strconv.Itoa(MaxRedirects))
+		}
+		if len(headers) > 0 {
+			headerFile, err := writePrivateFile(headers)
+			if err != nil {
+				return true, err
+			}
+			defer os.Remove(headerFile)
+			args = append(args, "--header", "@"+headerFile)
+		}
+		if opts.Proxy != "" {
+			args = append(args, "--proxy", opts.Proxy)
+		}
+		if !output.Progress() {
+			args = append(args, "--silent", "--show-error")
+		}
+		cmd = exec.Command(bin, append(args, url)...)
+	}
+
+	logExternal(name, url)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return true, fmt.Errorf("external downloader %s failed: %w", filepath.Base(cmd.Path), err)
+	}
+	return true, nil
+}
+
+// resolveRedirects 用内置客户端（遵守 RedirectHosts 与 MaxRedirects）发送 HEAD 请求，返回重定向后的最终地址
+func resolveRedirects(url string, opts DownloadOptions) (string, error) {
+	client, err := NewHTTPClient(30*time.Second, opts.Proxy, opts.RedirectHosts)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	ApplyHeaders(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", url, err)
+	}
+	resp.Body.Close()
+	LogFinalURL(resp, url)
+	return resp.Request.URL.String(), nil
+}
+
+// headerLines 将 GVM_HTTP_HEADERS 转换为外部工具使用的 "Name: value" 形式；
+// rawURL 不属于 SetHeaderHosts 允许的主机时返回空
+func headerLines(rawURL string) []string {
+	if u, err := neturl.Parse(rawURL); err != nil || !HeadersAllowed(u) {
+		return nil
+	}
+	var lines []string
+	for name, values := range ExtraHeaders() {
+		for _, v := range values {
+			lines = append(lines, name+": "+v)
+		}
+	}
+	return lines
+}
+
+// writePrivateFile 将 lines 写入只有当前用户可读写（0600）的临时文件，返回其路径
+func writePrivateFile(lines []string) (string, error) {
+	f, err := os.CreateTemp("", "gvm-headers-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	_, err = f.WriteString(strings.Join(lines, "\n") + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0600)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// logExternal 在 --verbose 时输出外部下载工具、下载地址与请求头，敏感请求头的取值会被隐藏
+func logExternal(name, url string) {
+	if !output.Verbose() {
+		return
+	}
+	output.PrintVerbose(fmt.Sprintf("downloading with %s: %s", name, redactURL(url)))
+	for _, line := range headerLines(url) {
+		header, value, _ := strings.Cut(line, ": ")
+		output.PrintVerbose(fmt.Sprintf("header %s: %s", header, RedactHeader(header, value)))
+	}
+}
+
+// redactURL 隐藏地址中的密码，无法解析时原样返回
+func redactURL(raw string) string {
+	if u, err := neturl.Parse(raw); err == nil {
+		return u.Redacted()
+	}
+	return raw
+}
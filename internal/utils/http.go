@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/output"
+)
+
+// DefaultUserAgent 是 gvm 发起 HTTP 请求时默认使用的 User-Agent
+const DefaultUserAgent = "gvm/1.0"
+
+// UserAgent 返回请求使用的 User-Agent，可通过 GVM_USER_AGENT 覆盖
+func UserAgent() string {
+	if v := strings.TrimSpace(os.Getenv("GVM_USER_AGENT")); v != "" {
+		return v
+	}
+	return DefaultUserAgent
+}
+
+// ExtraHeaders 解析 GVM_HTTP_HEADERS 中配置的额外请求头。
+// 格式为 "Name: value"，多个请求头之间用换行或分号分隔，例如：
+//
+//	GVM_HTTP_HEADERS="Authorization: Bearer xxx; X-Team: infra"
+func ExtraHeaders() http.Header {
+	headers := http.Header{}
+	raw := os.Getenv("GVM_HTTP_HEADERS")
+	if strings.TrimSpace(raw) == "" {
+		return headers
+	}
+	entries := strings.FieldsFunc(raw, func(r rune) bool { return r == '\n' || r == ';' })
+	for _, entry := range entries {
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers
+}
+
+// headerHosts 是可以收到 GVM_HTTP_HEADERS 的主机，见 SetHeaderHosts
+var headerHosts []string
+
+// SetHeaderHosts 设置可以收到 GVM_HTTP_HEADERS 的主机（通常是配置的下载镜像）。
+// 带端口的条目只匹配该端口；为空时不向任何主机发送这些请求头，
+// 以免私有镜像的令牌被发送到优先镜像、go.dev 或 GitHub 等第三方。
+func SetHeaderHosts(hosts []string) {
+	headerHosts = hosts
+}
+
+// HeadersAllowed 判断 GVM_HTTP_HEADERS 是否可以发送到 u 所在的主机
+func HeadersAllowed(u *url.URL) bool {
+	for _, h := range headerHosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+		if strings.Contains(h, ":") {
+			if strings.ToLower(u.Host) == h {
+				return true
+			}
+		} else if strings.ToLower(u.Hostname()) == h {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyHeaders 为请求设置 User-Agent，请求的主机在 SetHeaderHosts 设置的范围内时
+// 同时设置 GVM_HTTP_HEADERS 中配置的请求头
+func ApplyHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", UserAgent())
+	if !HeadersAllowed(req.URL) {
+		return
+	}
+	for name, values := range ExtraHeaders() {
+		req.Header.Del(name)
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+}
+
+// NewRequest 创建带有 gvm 请求头的 GET 请求
+func NewRequest(url string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	ApplyHeaders(req)
+	return req, nil
+}
+
+// NewPublicRequest 创建只带 User-Agent 的 GET 请求，用于 GitHub、go.dev 等不属于镜像的地址，
+// 无论 SetHeaderHosts 如何设置都不会带上 GVM_HTTP_HEADERS
+func NewPublicRequest(url string) (*http.Request, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// sensitiveHeaders 是输出日志时需要隐藏取值的请求头
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+	"X-Jfrog-Art-Api":     true,
+	"Private-Token":       true,
+}
+
+// RedactHeader 返回适合输出到日志的请求头取值，敏感请求头会被隐藏
+func RedactHeader(name, value string) string {
+	if sensitiveHeaders[http.CanonicalHeaderKey(name)] || strings.Contains(strings.ToLower(name), "token") {
+		return "[REDACTED]"
+	}
+	return value
+}
+
+// ProxyFunc 返回使用 proxy 的代理函数；proxy 为空时使用 HTTPS_PROXY 等标准环境变量
+func ProxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q", proxy)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// NewHTTPClient 创建用于版本列表、校验值等元数据请求的 HTTP 客户端，
+// redirectHosts 非空时只允许重定向到这些主机
+func NewHTTPClient(timeout time.Duration, proxy string, redirectHosts []string) (*http.Client, error) {
+	proxyFunc, err := ProxyFunc(proxy)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       timeout,
+		CheckRedirect: RedirectPolicy(redirectHosts),
+	}, nil
+}
+
+// StatusError 表示服务器返回了非预期的 HTTP 状态码
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "bad status: " + e.Status
+}
+
+// IsNotFound 判断错误链中是否包含 404 响应
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusNotFound
+}
+
+// MaxRedirects 是单个请求允许跟随的最大重定向次数
+const MaxRedirects = 10
+
+// RedirectPolicy 返回 http.Client 的 CheckRedirect：最多跟随 MaxRedirects 次重定向，
+// allowedHosts 非空时拒绝重定向到列表以外的主机（列表中的主机也匹配其子域名），
+// 并在 --verbose 时输出每一跳。
+func RedirectPolicy(allowedHosts []string) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= MaxRedirects {
+			return fmt.Errorf("stopped after %d redirects (last: %s)", MaxRedirects, req.URL.Redacted())
+		}
+		output.PrintVerbose(fmt.Sprintf("redirect %d: %s -> %s", len(via), via[len(via)-1].URL.Redacted(), req.URL.Redacted()))
+		if len(allowedHosts) > 0 && !hostAllowed(req.URL.Hostname(), allowedHosts) {
+			return fmt.Errorf("redirect to %s is not allowed (allowed hosts: %s)", req.URL.Hostname(), strings.Join(allowedHosts, ", "))
+		}
+		// http.Client 会把原请求的请求头带到重定向后的请求中，GVM_HTTP_HEADERS 只留给允许的主机
+		if !HeadersAllowed(req.URL) {
+			for name := range ExtraHeaders() {
+				req.Header.Del(name)
+			}
+		}
+		return nil
+	}
+}
+
+// LogFinalURL 在 --verbose 时输出经过重定向后实际响应请求的地址
+func LogFinalURL(resp *http.Response, requested string) {
+	if final := resp.Request.URL.Redacted(); final != requested {
+		output.PrintVerbose("resolved " + requested + " -> " + final)
+	}
+}
+
+// hostAllowed 判断 host 是否为 allowed 中的某个主机或其子域名
+func hostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(host)
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if host == a || strings.HasSuffix(host, "."+a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 锁文件的等待与过期设置
+const (
+	// LockTimeout 是等待其他进程释放锁的最长时间
+	LockTimeout = 10 * time.Minute
+	// LockStaleAfter 超过该时间未刷新的锁视为持有进程已退出（被杀死、断电等）
+	LockStaleAfter = 2 * time.Minute
+	// lockHeartbeat 是持有锁期间刷新锁文件修改时间的间隔
+	lockHeartbeat = 30 * time.Second
+)
+
+// FileLock 是基于 O_EXCL 创建锁文件实现的跨进程互斥锁
+type FileLock struct {
+	path string
+	stop chan struct{}
+}
+
+// GetLocksDir 返回锁文件目录（~/.gvm/locks）
+func GetLocksDir() (string, error) {
+	home, err := GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gvm", "locks"), nil
+}
+
+// AcquireLock 获取名为 name 的锁（~/.gvm/locks/<name>.lock），必要时等待其他进程释放。
+// 持有期间定期刷新锁文件的修改时间；超过 LockStaleAfter 未刷新的锁会被视为过期并接管，
+// 等待超过 timeout 时返回错误。waited 表示是否曾等待其他进程。
+func AcquireLock(name string, timeout time.Duration) (lock *FileLock, waited bool, err error) {
+	dir, err := GetLocksDir()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := EnsureDir(dir); err != nil {
+		return nil, false, fmt.Errorf("failed to create locks directory: %w", err)
+	}
+	path := filepath.Join(dir, name+".lock")
+	deadline := time.Now().Add(timeout)
+	notified := false
+
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			lock := &FileLock{path: path, stop: make(chan struct{})}
+			go lock.heartbeat()
+			return lock, waited, nil
+		}
+		if !os.IsExist(err) {
+			return nil, waited, fmt.Errorf("failed to create lock %s: %w", path, err)
+		}
+
+		// 锁已存在：过期则删除后重试，否则等待
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > LockStaleAfter {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, waited, fmt.Errorf("timed out after %s waiting for lock %s; remove it if no other gvm process is running", timeout, path)
+		}
+		if !notified {
+			fmt.Printf("Waiting for another gvm process (lock %s)...\n", path)
+			notified = true
+		}
+		waited = true
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// heartbeat 定期刷新锁文件的修改时间，表明持有进程仍在运行
+func (l *FileLock) heartbeat() {
+	ticker := time.NewTicker(lockHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			_ = os.Chtimes(l.path, now, now)
+		}
+	}
+}
+
+// Release 释放锁并删除锁文件
+func (l *FileLock) Release() {
+	close(l.stop)
+	_ = os.Remove(l.path)
+}
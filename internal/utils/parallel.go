@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/philokun/gvm/internal/output"
+)
+
+// minPartSize 是分段下载中每段的最小大小，不足两段的文件直接顺序下载
+const minPartSize = 4 << 20
+
+// downloadInParts 用多个连接并行下载 url 的不同字节范围，写入 partPath 的对应位置。
+//
+// 各段乱序完成，SHA256 却必须按顺序计算：主 goroutine 记录已完成的连续前缀，
+// 前缀一增长就从文件中读出新增部分（刚写入，通常仍在页缓存中）写入摘要。第一段下载时
+// 摘要与下载同步推进；后面的段要等前面的段完成后才能计算，最坏情况下（第一段最后完成）
+// 相当于下载结束后再顺序读一遍大部分文件。服务器不提供按范围的摘要，因此不尝试合并各段的摘要。
+//
+// 服务器不支持 Range 或文件太小时返回 handled=false，调用方应回退到顺序下载。
+// 分段下载不支持断点续传，失败时删除部分文件。
+func downloadInParts(client *http.Client, url, partPath string, opts DownloadOptions) (sum string, handled bool, err error) {
+	finalURL, size, err := probeRanges(client, url)
+	if err != nil {
+		output.PrintVerbose(fmt.Sprintf("Downloading sequentially: %v", err))
+		return "", false, nil
+	}
+	parts := opts.Parts
+	if max := int(size / minPartSize); parts > max {
+		parts = max
+	}
+	if parts < 2 {
+		output.PrintVerbose(fmt.Sprintf("Downloading sequentially: %d bytes is too small to split", size))
+		return "", false, nil
+	}
+
+	removePartial(partPath)
+	out, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return "", true, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer out.Close()
+	if err := out.Truncate(size); err != nil {
+		removePartial(partPath)
+		return "", true, fmt.Errorf("failed to allocate %s: %w", partPath, err)
+	}
+
+	fmt.Printf("Downloading in %d parts\n", parts)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	starts := make([]int64, parts+1)
+	for i := range starts {
+		starts[i] = size * int64(i) / int64(parts)
+	}
+	written := make([]int64, parts)
+	notify := make(chan struct{}, 1)
+	errc := make(chan error, parts)
+	for i := 0; i < parts; i++ {
+		go func(i int) {
+			errc <- fetchRange(ctx, client, finalURL, out, starts[i], starts[i+1], &written[i], notify)
+		}(i)
+	}
+
+	hasher := sha256.New()
+	var hashed int64
+	buf := make([]byte, 1024*1024)
+	progress := newDownloadProgress(size, 0)
+	var firstErr error
+	for remaining := parts; remaining > 0; {
+		select {
+		case err := <-errc:
+			remaining--
+			if err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		case <-notify:
+		}
+		if firstErr != nil {
+			continue
+		}
+
+		// 已完成的连续前缀：第一个未完成的段之前的全部字节，加上该段已写入的部分
+		var prefix, total int64
+		complete := true
+		for i := range written {
+			n := atomic.LoadInt64(&written[i])
+			total += n
+			if complete {
+				prefix = starts[i] + n
+				complete = n == starts[i+1]-starts[i]
+			}
+		}
+		if prefix > hashed {
+			if _, err := io.CopyBuffer(hasher, io.NewSectionReader(out, hashed, prefix-hashed), buf); err != nil {
+				firstErr = fmt.Errorf("failed to hash download: %w", err)
+				cancel()
+				continue
+			}
+			hashed = prefix
+		}
+		progress.update(total)
+	}
+	if firstErr == nil && hashed != size {
+		firstErr = fmt.Errorf("download ended after %d of %d bytes", hashed, size)
+	}
+	if firstErr != nil {
+		out.Close()
+		removePartial(partPath)
+		return "", true, fmt.Errorf("failed to download file: %w", firstErr)
+	}
+	progress.done(size)
+
+	if err := out.Sync(); err != nil {
+		return "", true, fmt.Errorf("failed to flush file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", true, fmt.Errorf("failed to close temp file: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), true, nil
+}
+
+// probeRanges 请求第一个字节，确认服务器支持 Range 并返回（跟随重定向后的）最终地址与文件大小
+func probeRanges(client *http.Client, url string) (string, int64, error) {
+	req, err := NewRequest(url)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Accept-Encoding", "identity")
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+	LogFinalURL(resp, url)
+	if resp.StatusCode != http.StatusPartialContent {
+		if resp.StatusCode == http.StatusOK {
+			return "", 0, fmt.Errorf("the server does not support range requests")
+		}
+		return "", 0, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	size := contentRangeTotal(resp.Header.Get("Content-Range"))
+	if size <= 0 {
+		return "", 0, fmt.Errorf("the server did not report the file size")
+	}
+	return resp.Request.URL.String(), size, nil
+}
+
+// fetchRange 下载 [start, end) 范围写入 out 的对应位置，并在 written 中累计已写入的字节数
+func fetchRange(ctx context.Context, client *http.Client, url string, out *os.File, start, end int64, written *int64, notify chan<- struct{}) error {
+	req, err := NewRequest(url)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept-Encoding", "identity")
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || contentRangeStart(resp.Header.Get("Content-Range")) != start {
+		return fmt.Errorf("unexpected response %q for range %d-%d", resp.Status, start, end-1)
+	}
+
+	buf := make([]byte, 256*1024)
+	off := start
+	for off < end {
+		n, err := resp.Body.Read(buf[:min(int64(len(buf)), end-off)])
+		if n > 0 {
+			if _, werr := out.WriteAt(buf[:n], off); werr != nil {
+				return fmt.Errorf("failed to write download: %w", werr)
+			}
+			off += int64(n)
+			atomic.AddInt64(written, int64(n))
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if off != end {
+		return fmt.Errorf("range %d-%d: %w", start, end-1, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// contentRangeTotal 解析 Content-Range 头（bytes start-end/total）中的完整大小，未知或失败返回 -1
+func contentRangeTotal(header string) int64 {
+	slash := strings.LastIndex(header, "/")
+	if slash < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(header[slash+1:]), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 未配置 GVM_DIR_MODE / GVM_FILE_MODE 时使用的默认权限
+const (
+	DefaultDirMode  os.FileMode = 0755
+	DefaultFileMode os.FileMode = 0644
+)
+
+// Modes 是 gvm 创建目录与解压普通文件时使用的权限，0 表示未配置。
+// 多用户共享安装时可以通过 GVM_DIR_MODE=0775、GVM_FILE_MODE=0664 让同组用户管理安装目录。
+type Modes struct {
+	Dir  os.FileMode
+	File os.FileMode
+}
+
+// LoadModes 从 GVM_DIR_MODE 与 GVM_FILE_MODE 读取权限配置
+func LoadModes() (Modes, error) {
+	var m Modes
+	var err error
+	if m.Dir, err = modeFromEnv("GVM_DIR_MODE"); err != nil {
+		return Modes{}, err
+	}
+	if m.File, err = modeFromEnv("GVM_FILE_MODE"); err != nil {
+		return Modes{}, err
+	}
+	return m, nil
+}
+
+func modeFromEnv(name string) (os.FileMode, error) {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return 0, nil
+	}
+	mode, err := ParseMode(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return mode, nil
+}
+
+// ParseMode 解析八进制权限，例如 "0775" 或 "664"
+func ParseMode(s string) (os.FileMode, error) {
+	n, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(s), "0o"), 8, 32)
+	if err != nil || n == 0 || n > 0777 {
+		return 0, fmt.Errorf("%q is not an octal permission between 0001 and 0777", s)
+	}
+	return os.FileMode(n), nil
+}
+
+// MkdirAll 创建目录。配置了目录权限时使用该权限，并对新建的每一级目录显式 chmod，
+// 使结果不受 umask 影响；否则使用 fallback（为 0 时为 DefaultDirMode）。
+func (m Modes) MkdirAll(path string, fallback os.FileMode) error {
+	if m.Dir == 0 {
+		if fallback == 0 {
+			fallback = DefaultDirMode
+		}
+		return os.MkdirAll(path, fallback)
+	}
+
+	var created []string
+	for p := filepath.Clean(path); !FileExists(p); p = filepath.Dir(p) {
+		created = append(created, p)
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+	if err := os.MkdirAll(path, m.Dir); err != nil {
+		return err
+	}
+	for _, p := range created {
+		if err := os.Chmod(p, m.Dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileMode 返回解压普通文件时使用的权限。可执行文件保留归档中的权限，
+// 其他文件在配置了文件权限时使用该权限；custom 表示需要在创建后显式 chmod。
+func (m Modes) fileMode(name string, archiveMode int64) (mode os.FileMode, custom bool) {
+	mode = os.FileMode(toolchainMode(name, archiveMode))
+	if m.File == 0 || mode&0111 != 0 {
+		return mode, false
+	}
+	return m.File, true
+}
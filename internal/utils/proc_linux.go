@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processInfo 从 /proc/<pid>/stat 读取进程名与父进程 ID
+func processInfo(pid int) (string, int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", 0, err
+	}
+	// 格式为 "pid (comm) state ppid ..."，comm 中可能含空格与括号，取最后一个 ')'
+	s := string(data)
+	open, end := strings.Index(s, "("), strings.LastIndex(s, ")")
+	if open < 0 || end < open {
+		return "", 0, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(s[end+1:])
+	if len(fields) < 2 {
+		return "", 0, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed /proc/%d/stat: %w", pid, err)
+	}
+	return s[open+1 : end], ppid, nil
+}
@@ -0,0 +1,28 @@
+//go:build !linux && !windows
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// processInfo 通过 ps 读取进程名与父进程 ID（macOS 与其他 Unix 没有 /proc）
+func processInfo(pid int) (string, int, error) {
+	out, err := exec.Command("ps", "-o", "ppid=", "-o", "comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", 0, err
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return "", 0, fmt.Errorf("unexpected ps output %q", strings.TrimSpace(string(out)))
+	}
+	ppid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return "", 0, fmt.Errorf("unexpected ps output %q", strings.TrimSpace(string(out)))
+	}
+	// comm 可能是含空格的完整路径
+	return strings.Join(fields[1:], " "), ppid, nil
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// processInfo 通过进程快照读取进程的映像名（如 pwsh.exe）与父进程 ID
+func processInfo(pid int) (string, int, error) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return "", 0, err
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = syscall.Process32First(snapshot, &entry); err == nil; err = syscall.Process32Next(snapshot, &entry) {
+		if int(entry.ProcessID) == pid {
+			return syscall.UTF16ToString(entry.ExeFile[:]), int(entry.ParentProcessID), nil
+		}
+	}
+	return "", 0, fmt.Errorf("process %d not found", pid)
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// partialMeta 记录部分下载文件的来源信息，用于判断能否安全续传
+type partialMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Size         int64  `json:"size"` // 完整文件大小，未知时为 -1
+}
+
+// partialPath 返回 URL 对应的部分下载文件路径
+func partialPath(dir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "gvm-download-"+hex.EncodeToString(sum[:8])+".part")
+}
+
+// removePartial 删除部分下载文件及其元数据
+func removePartial(partPath string) {
+	_ = os.Remove(partPath)
+	_ = os.Remove(partPath + ".meta")
+}
+
+// resumableOffset 返回可以续传的偏移量；部分文件不存在或与 URL/大小不匹配时返回 0 并清理
+func resumableOffset(partPath, url string) (int64, partialMeta) {
+	var meta partialMeta
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return 0, meta
+	}
+	data, err := os.ReadFile(partPath + ".meta")
+	if err != nil || json.Unmarshal(data, &meta) != nil || meta.URL != url {
+		removePartial(partPath)
+		return 0, partialMeta{}
+	}
+	size := info.Size()
+	if size == 0 || (meta.Size > 0 && size >= meta.Size) {
+		// 空文件或已达到/超过完整大小：无法安全续传
+		removePartial(partPath)
+		return 0, partialMeta{}
+	}
+	return size, meta
+}
+
+// writePartialMeta 写入部分下载文件的元数据
+func writePartialMeta(partPath string, meta partialMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal download metadata: %w", err)
+	}
+	if err := os.WriteFile(partPath+".meta", data, 0644); err != nil {
+		return fmt.Errorf("failed to write download metadata: %w", err)
+	}
+	return nil
+}
+
+// contentRangeStart 解析 Content-Range 头（bytes start-end/total）中的起始偏移，失败返回 -1
+func contentRangeStart(header string) int64 {
+	header = strings.TrimSpace(strings.TrimPrefix(header, "bytes"))
+	dash := strings.Index(header, "-")
+	if dash <= 0 {
+		return -1
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(header[:dash]), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/philokun/gvm/internal/output"
+)
+
+// 检测到当前 shell 的方式
+const (
+	ShellFromFlag   = "--shell"
+	ShellFromParent = "parent process"
+	ShellFromEnv    = "$SHELL"
+)
+
+// shellOverride 是 --shell 指定的 shell，为空时自动检测
+var shellOverride string
+
+// SetShellOverride 设置 --shell 指定的 shell，优先于自动检测
+func SetShellOverride(name string) {
+	shellOverride = normalizeShellName(name)
+}
+
+// maxShellAncestors 是向上查找 shell 时检查的最大祖先进程数（gvm 可能经由 make、sudo 等启动）
+const maxShellAncestors = 5
+
+// knownShells 是父进程链中识别为用户 shell 的进程名。sh、dash 等通常只是 make 或 sh -c
+// 用来执行脚本的，不在其中，查找会越过它们继续向上
+var knownShells = map[string]bool{
+	"bash": true, "zsh": true, "fish": true, "powershell": true, "pwsh": true, "cmd": true,
+}
+
+var (
+	parentShellOnce sync.Once
+	parentShell     string
+)
+
+// DetectShell 返回运行 gvm 的 shell 名称（如 zsh）与检测方式。
+// 优先使用 --shell；其次查找父进程（以及更上层的祖先进程）中的 shell，
+// 这样从登录 shell 为 bash 的终端中启动的 fish 也能被正确识别；最后回退到 $SHELL。
+// 都无法确定时返回空字符串。
+func DetectShell() (name, source string) {
+	if shellOverride != "" {
+		return shellOverride, ShellFromFlag
+	}
+	parentShellOnce.Do(func() {
+		parentShell = findParentShell()
+		if parentShell != "" {
+			output.PrintVerbose(fmt.Sprintf("Detected shell %s from the parent process", parentShell))
+		}
+	})
+	if parentShell != "" {
+		return parentShell, ShellFromParent
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return normalizeShellName(shell), ShellFromEnv
+	}
+	return "", ""
+}
+
+// findParentShell 沿父进程链向上查找第一个 shell 进程
+func findParentShell() string {
+	pid := os.Getppid()
+	for i := 0; i < maxShellAncestors && pid > 1; i++ {
+		name, ppid, err := processInfo(pid)
+		if err != nil {
+			output.PrintVerbose(fmt.Sprintf("Cannot inspect process %d: %v", pid, err))
+			return ""
+		}
+		if name = normalizeShellName(name); knownShells[name] {
+			return name
+		}
+		pid = ppid
+	}
+	return ""
+}
+
+// normalizeShellName 将路径或进程名规范化为 shell 名称：去掉目录、登录 shell 的 - 前缀与 .exe 后缀
+func normalizeShellName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimPrefix(name, "-")
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
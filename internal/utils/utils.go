@@ -4,9 +4,11 @@ import (
     "archive/tar"
     "archive/zip"
     "bufio"
+    "compress/bzip2"
     "compress/gzip"
     "crypto/sha256"
     "encoding/hex"
+    "errors"
     "fmt"
     "io"
     "net/http"
@@ -14,168 +16,406 @@ import (
     "path/filepath"
     "runtime"
     "strings"
+    "sync"
+    "syscall"
     "time"
+
+    "github.com/philokun/gvm/internal/output"
 )
 
 // DownloadFile 下载文件到指定路径（保持向后兼容）
 func DownloadFile(url, destPath string) error {
-	return DownloadFileWithProgress(url, destPath, 0)
+	_, err := DownloadFileWithProgress(url, destPath, 0)
+	return err
 }
 
-// DownloadFileWithProgress 下载文件到指定路径，带进度显示
-func DownloadFileWithProgress(url, destPath string, expectedSize int64) error {
+// DownloadFileWithProgress 下载文件到指定路径，带进度显示（默认启用断点续传），
+// 返回下载内容的 SHA256 摘要
+func DownloadFileWithProgress(url, destPath string, expectedSize int64) (string, error) {
+	return DownloadFileWithOptions(url, destPath, DownloadOptions{ExpectedSize: expectedSize, Resume: true})
+}
+
+// DownloadOptions 控制下载行为的可选参数
+type DownloadOptions struct {
+	ExpectedSize int64  // 期望的文件大小，服务器未返回 Content-Length 时用于显示进度
+	Resume       bool   // 是否从上次中断的部分文件继续下载
+	Proxy        string // HTTP(S) 代理地址，为空时使用 HTTPS_PROXY 等标准环境变量
+	Downloader   string // 下载工具（builtin、aria2、curl），为空时使用内置下载器
+	Parts        int    // 内置下载器的最大并行连接数，大于 1 时按字节范围分段下载，见 downloadInParts
+
+	RedirectHosts []string // 允许重定向到的主机，为空时不限制
+}
+
+// DownloadFileWithOptions 下载文件到指定路径，带进度显示，返回下载内容的 SHA256 摘要（十六进制）。
+// 摘要在下载过程中计算，调用方可用 MatchSHA256 校验而无需再次读取文件。
+//
+// 断点续传的生命周期：
+//  1. 下载写入与目标文件同目录、按 URL 命名的部分文件（gvm-download-<hash>.part），
+//     同时写入 .meta 记录 URL、ETag、Last-Modified 和总大小；
+//  2. 中断后部分文件与 .meta 保留；再次下载同一 URL 时，若 .meta 与 URL 匹配且
+//     部分文件不大于记录的总大小，则发送 Range 与 If-Range 请求继续下载；
+//  3. 服务器返回 206 且 Content-Range 起点一致时追加写入；返回 200（不支持 Range
+//     或 ETag 已变化）时截断重新下载；416 时丢弃部分文件重新开始；
+//  4. 下载完成后部分文件重命名为目标文件并删除 .meta。
+//
+// Resume 为 false 时会先删除已有的部分文件，强制完整下载。
+func DownloadFileWithOptions(url, destPath string, opts DownloadOptions) (string, error) {
+	// 如果配置了外部下载工具（GVM_DOWNLOADER=aria2|curl）且可用，则交给外部工具处理
+	if handled, err := downloadWithExternal(url, destPath, opts); handled {
+		if err != nil {
+			return "", err
+		}
+		// 外部工具写入的文件只能在下载后再读一遍计算摘要
+		return ComputeSHA256(destPath)
+	}
+
+	proxy, err := ProxyFunc(opts.Proxy)
+	if err != nil {
+		return "", err
+	}
+
 	// 优化 HTTP 客户端：使用更激进的设置以提高下载速度
 	transport := &http.Transport{
+		Proxy:                 proxy,
 		DisableCompression:    true, // 文件已压缩，不需要再次压缩
 		MaxIdleConns:          100,
-		MaxIdleConnsPerHost:    10,
-		IdleConnTimeout:        90 * time.Second,
-		ResponseHeaderTimeout:  30 * time.Second,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 		// 禁用 HTTP/2，使用 HTTP/1.1 可能在某些情况下更快
 		ForceAttemptHTTP2: false,
 	}
 	client := &http.Client{
-		Transport: transport,
-		Timeout:   0, // 无超时限制，因为文件可能很大
+		Transport:     transport,
+		Timeout:       0, // 无超时限制，因为文件可能很大
+		CheckRedirect: RedirectPolicy(opts.RedirectHosts),
+	}
+
+	dir := filepath.Dir(destPath)
+	if err := EnsureDir(dir); err != nil {
+		return "", fmt.Errorf("failed to ensure download dir: %w", err)
+	}
+
+	partPath := partialPath(dir, url)
+	if !opts.Resume {
+		removePartial(partPath)
 	}
-	
-	req, err := http.NewRequest("GET", url, nil)
+	offset, meta := resumableOffset(partPath, url)
+
+	// 多连接下载（没有可续传的部分文件时）；服务器不支持 Range 时回退到下面的顺序下载
+	if opts.Parts > 1 && offset == 0 {
+		if sum, handled, err := downloadInParts(client, url, partPath, opts); handled {
+			if err != nil {
+				return "", err
+			}
+			if err := finishPartial(partPath, destPath); err != nil {
+				return "", err
+			}
+			return sum, nil
+		}
+	}
+
+	req, err := NewRequest(url)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", err
 	}
-	
+
 	// 设置请求头，优化下载
-	req.Header.Set("User-Agent", "gvm/1.0")
 	req.Header.Set("Accept-Encoding", "identity") // 禁用压缩，因为文件已压缩
-	req.Header.Set("Connection", "keep-alive")     // 保持连接
-	
+	req.Header.Set("Connection", "keep-alive")    // 保持连接
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		// If-Range 保证远端文件变化时服务器返回完整内容而不是错误的片段
+		if meta.ETag != "" {
+			req.Header.Set("If-Range", meta.ETag)
+		} else if meta.LastModified != "" {
+			req.Header.Set("If-Range", meta.LastModified)
+		}
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
+		return "", fmt.Errorf("failed to download file: %w", err)
 	}
 	defer resp.Body.Close()
+	LogFinalURL(resp, url)
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0 && contentRangeStart(resp.Header.Get("Content-Range")) == offset:
+		fmt.Printf("Resuming download at %.2f MB\n", float64(offset)/(1024*1024))
+	case resp.StatusCode == http.StatusOK:
+		// 服务器不支持 Range 或文件已变化：从头开始
+		offset = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// 部分文件无效，丢弃后由调用方重试
+		removePartial(partPath)
+		return "", fmt.Errorf("bad status: %s (discarded partial download)", resp.Status)
+	case resp.StatusCode == http.StatusPartialContent:
+		// Content-Range 与本地部分文件不一致，丢弃后由调用方重试
+		removePartial(partPath)
+		return "", fmt.Errorf("unexpected partial content range %q (discarded partial download)", resp.Header.Get("Content-Range"))
+	default:
+		return "", &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
-	// 获取实际文件大小
+	// 获取实际文件大小（断点续传时加上已有的部分）
 	contentLength := resp.ContentLength
-	if contentLength == -1 && expectedSize > 0 {
-		contentLength = expectedSize
+	if contentLength >= 0 {
+		contentLength += offset
+	} else if opts.ExpectedSize > 0 {
+		contentLength = opts.ExpectedSize
 	}
 
-	dir := filepath.Dir(destPath)
-	if err := EnsureDir(dir); err != nil {
-		return fmt.Errorf("failed to ensure download dir: %w", err)
+	flags := os.O_CREATE | os.O_WRONLY
+	if offset > 0 {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
 	}
-	
-	out, err := os.CreateTemp(dir, "gvm-download-*")
+	out, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
-	tempName := out.Name()
 	defer out.Close()
 
+	if err := writePartialMeta(partPath, partialMeta{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Size:         contentLength,
+	}); err != nil {
+		return "", err
+	}
+
 	// 使用 io.CopyBuffer 而不是手动循环，Go 标准库已经高度优化
 	// 使用更大的缓冲区（1MB）以提高速度
 	buf := make([]byte, 1024*1024) // 1MB 缓冲区
-	
+
 	// 使用带缓冲的写入
 	bufferedOut := bufio.NewWriterSize(out, 4*1024*1024) // 4MB 写入缓冲区
-	defer bufferedOut.Flush()
-	
+
 	// 创建带进度跟踪的 Reader
-	startTime := time.Now()
-	lastUpdateTime := startTime
-	lastWritten := int64(0)
-	lastProgress := int64(-1)
-	
+	progress := newDownloadProgress(contentLength, offset)
 	progressReader := &progressReader{
 		reader:        resp.Body,
 		contentLength: contentLength,
-		onProgress: func(written int64) {
-			now := time.Now()
-			if contentLength > 0 {
-				progress := (written * 100) / contentLength
-				elapsed := now.Sub(startTime).Seconds()
-				shouldUpdate := (progress != lastProgress && progress%2 == 0) || 
-				               (now.Sub(lastUpdateTime) >= 500*time.Millisecond)
-				if shouldUpdate && elapsed > 0 {
-					// 计算瞬时速度（最近0.5秒的速度）
-					timeDiff := now.Sub(lastUpdateTime).Seconds()
-					if timeDiff > 0 {
-						recentSpeed := float64(written-lastWritten) / timeDiff
-						
-						fmt.Printf("\rProgress: %d%% (%.2f MB / %.2f MB) - %.2f MB/s", 
-							progress, 
-							float64(written)/(1024*1024), 
-							float64(contentLength)/(1024*1024),
-							recentSpeed/(1024*1024))
-						lastProgress = progress
-						lastUpdateTime = now
-						lastWritten = written
-					}
-				}
-			}
-		},
+		written:       offset,
+		onProgress:    progress.update,
 	}
-	
+
+	// 边下载边计算 SHA256，避免下载完成后再次读取整个文件；续传时先计算已有部分
+	hasher := sha256.New()
+	if offset > 0 {
+		if err := hashPrefix(hasher, partPath, offset); err != nil {
+			return "", err
+		}
+	}
+
 	// 使用 io.CopyBuffer 进行高效复制
-	written, err := io.CopyBuffer(bufferedOut, progressReader, buf)
+	written, err := io.CopyBuffer(io.MultiWriter(bufferedOut, hasher), progressReader, buf)
+	if flushErr := bufferedOut.Flush(); err == nil {
+		err = flushErr
+	}
 	if err != nil {
-		os.Remove(tempName)
-		return fmt.Errorf("failed to download file: %w", err)
-	}
-	
-	// 完成进度显示
-	if contentLength > 0 {
-		elapsed := time.Since(startTime).Seconds()
-		avgSpeed := float64(written) / elapsed
-		fmt.Printf("\rProgress: 100%% (%.2f MB / %.2f MB) - Complete! (%.2f MB/s avg)\n",
-			float64(written)/(1024*1024),
-			float64(contentLength)/(1024*1024),
-			avgSpeed/(1024*1024))
-	}
-	
+		// 保留部分文件以便下次续传
+		if !opts.Resume {
+			removePartial(partPath)
+		}
+		return "", fmt.Errorf("failed to download file: %w", err)
+	}
+
+	progress.done(written + offset)
+
 	if err := out.Sync(); err != nil {
-		os.Remove(tempName)
-		return fmt.Errorf("failed to flush file: %w", err)
+		return "", fmt.Errorf("failed to flush file: %w", err)
 	}
 	if err := out.Close(); err != nil {
-		os.Remove(tempName)
-		return fmt.Errorf("failed to close temp file: %w", err)
+		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
-	
+
+	if err := finishPartial(partPath, destPath); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// finishPartial 将下载完成的部分文件移动到目标路径并删除其元数据
+func finishPartial(partPath, destPath string) error {
 	if FileExists(destPath) {
 		_ = os.Remove(destPath)
 	}
-	if err := os.Rename(tempName, destPath); err != nil {
+	if err := MoveFile(partPath, destPath); err != nil {
+		return err
+	}
+	_ = os.Remove(partPath + ".meta")
+	return nil
+}
+
+// hashPrefix 将文件前 n 个字节写入 hasher，用于续传时补齐已下载部分的摘要
+func hashPrefix(hasher io.Writer, path string, n int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open partial download: %w", err)
+	}
+	defer f.Close()
+	if _, err := io.CopyN(hasher, f, n); err != nil {
+		return fmt.Errorf("failed to hash partial download: %w", err)
+	}
+	return nil
+}
+
+// MoveFile 将文件移动到目标路径，跨文件系统时回退到复制
+func MoveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err != nil {
 		// 回退到复制方案
-		in, errOpen := os.Open(tempName)
+		in, errOpen := os.Open(src)
 		if errOpen != nil {
-			os.Remove(tempName)
 			return fmt.Errorf("failed to move file: %w", err)
 		}
 		defer in.Close()
-		outFinal, errCreate := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		outFinal, errCreate := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 		if errCreate != nil {
-			os.Remove(tempName)
 			return fmt.Errorf("failed to move file: %w", err)
 		}
 		if _, errCopy := io.Copy(outFinal, in); errCopy != nil {
 			outFinal.Close()
-			os.Remove(tempName)
 			return fmt.Errorf("failed to move file: %w", err)
 		}
 		outFinal.Close()
-		os.Remove(tempName)
+		in.Close()
+		os.Remove(src)
 	}
-
 	return nil
 }
 
+// RenameDir 将目录 src 移动到 dst（dst 不能已存在）。优先使用原子的 os.Rename；
+// 两者位于不同文件系统（EXDEV）时回退为复制整个目录树后删除 src，复制失败时删除不完整的 dst。
+func RenameDir(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree 递归复制目录，保留文件权限与符号链接
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			in, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+			return extractFile(in, target, info.Mode().Perm(), true)
+		}
+	})
+}
+
+// downloadProgress 显示下载进度。关闭进度（--no-progress、CI）时不输出；
+// 输出不是终端时按 10% 逐行输出，不使用 \r
+type downloadProgress struct {
+	contentLength  int64
+	offset         int64 // 续传时已有的字节数，不计入平均速度
+	show           bool
+	inPlace        bool
+	startTime      time.Time
+	lastUpdateTime time.Time
+	lastWritten    int64
+	lastProgress   int64
+}
+
+func newDownloadProgress(contentLength, offset int64) *downloadProgress {
+	now := time.Now()
+	return &downloadProgress{
+		contentLength:  contentLength,
+		offset:         offset,
+		show:           output.Progress() && contentLength > 0,
+		inPlace:        output.StdoutIsTerminal(),
+		startTime:      now,
+		lastUpdateTime: now,
+		lastWritten:    offset,
+		lastProgress:   -1,
+	}
+}
+
+// update 在已写入 written 字节（含续传的部分）时调用
+func (p *downloadProgress) update(written int64) {
+	if !p.show {
+		return
+	}
+	now := time.Now()
+	progress := (written * 100) / p.contentLength
+	if !p.inPlace {
+		if progress/10 != p.lastProgress/10 && progress < 100 {
+			fmt.Printf("Progress: %d%% (%.2f MB / %.2f MB)\n",
+				progress/10*10,
+				float64(written)/(1024*1024),
+				float64(p.contentLength)/(1024*1024))
+			p.lastProgress = progress
+		}
+		return
+	}
+	elapsed := now.Sub(p.startTime).Seconds()
+	shouldUpdate := (progress != p.lastProgress && progress%2 == 0) ||
+		(now.Sub(p.lastUpdateTime) >= 500*time.Millisecond)
+	if shouldUpdate && elapsed > 0 {
+		// 计算瞬时速度（最近0.5秒的速度）
+		timeDiff := now.Sub(p.lastUpdateTime).Seconds()
+		if timeDiff > 0 {
+			recentSpeed := float64(written-p.lastWritten) / timeDiff
+
+			fmt.Printf("\rProgress: %d%% (%.2f MB / %.2f MB) - %.2f MB/s",
+				progress,
+				float64(written)/(1024*1024),
+				float64(p.contentLength)/(1024*1024),
+				recentSpeed/(1024*1024))
+			p.lastProgress = progress
+			p.lastUpdateTime = now
+			p.lastWritten = written
+		}
+	}
+}
+
+// done 输出完成行（平均速度只统计本次传输的字节）
+func (p *downloadProgress) done(total int64) {
+	if !p.show {
+		return
+	}
+	elapsed := time.Since(p.startTime).Seconds()
+	avgSpeed := float64(total-p.offset) / elapsed
+	prefix := ""
+	if p.inPlace {
+		prefix = "\r"
+	}
+	fmt.Printf("%sProgress: 100%% (%.2f MB / %.2f MB) - Complete! (%.2f MB/s avg)\n", prefix,
+		float64(total)/(1024*1024),
+		float64(p.contentLength)/(1024*1024),
+		avgSpeed/(1024*1024))
+}
+
 // progressReader 包装 io.Reader 以跟踪下载进度
 type progressReader struct {
 	reader        io.Reader
@@ -195,6 +435,10 @@ func (pr *progressReader) Read(p []byte) (n int, err error) {
 
 // ExtractTarGz 解压 tar.gz 文件到指定目录
 func ExtractTarGz(tarGzPath, destPath string) error {
+	return extractTarGz(tarGzPath, destPath, ExtractOptions{})
+}
+
+func extractTarGz(tarGzPath, destPath string, opts ExtractOptions) error {
 	// 打开 tar.gz 文件
 	file, err := os.Open(tarGzPath)
 	if err != nil {
@@ -209,11 +453,36 @@ func ExtractTarGz(tarGzPath, destPath string) error {
 	}
 	defer gzReader.Close()
 
+	return extractTar(gzReader, destPath, opts)
+}
+
+// ExtractTarBz2 解压 tar.bz2 文件到指定目录
+func ExtractTarBz2(tarBz2Path, destPath string) error {
+	return extractTarBz2(tarBz2Path, destPath, ExtractOptions{})
+}
+
+func extractTarBz2(tarBz2Path, destPath string, opts ExtractOptions) error {
+	file, err := os.Open(tarBz2Path)
+	if err != nil {
+		return fmt.Errorf("failed to open tar.bz2 file: %w", err)
+	}
+	defer file.Close()
+
+	return extractTar(bzip2.NewReader(file), destPath, opts)
+}
+
+// extractTar 从解压后的 tar 流中提取文件（去除顶层 go/ 前缀）
+func extractTar(r io.Reader, destPath string, opts ExtractOptions) error {
 	// 创建 tar 读取器
-	tarReader := tar.NewReader(gzReader)
+	tarReader := tar.NewReader(r)
+
+	modes, err := LoadModes()
+	if err != nil {
+		return err
+	}
 
 	// 创建目标目录
-	if err := os.MkdirAll(destPath, 0755); err != nil {
+	if err := modes.MkdirAll(destPath, 0); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
@@ -228,47 +497,70 @@ func ExtractTarGz(tarGzPath, destPath string) error {
 		}
 
 		// 构建目标路径
-		targetPath := filepath.Join(destPath, strings.TrimPrefix(header.Name, "go/"))
+		name := opts.trim(header.Name)
+		if opts.excluded(name) {
+			continue
+		}
+		targetPath, err := safeJoin(destPath, name)
+		if err != nil {
+			return err
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
-			if err := os.MkdirAll(targetPath, os.FileMode(header.Mode)); err != nil {
+			if err := modes.MkdirAll(targetPath, os.FileMode(header.Mode).Perm()); err != nil {
 				return fmt.Errorf("failed to create directory: %w", err)
 			}
 		case tar.TypeReg:
-			if err := extractFile(tarReader, targetPath, header.Mode); err != nil {
+			mode, custom := modes.fileMode(name, header.Mode)
+			if err := extractFile(tarReader, targetPath, mode, custom); err != nil {
 				return fmt.Errorf("failed to extract file: %w", err)
 			}
 		}
 	}
 
-    return nil
+	return nil
 }
 
 // ExtractZip 解压 zip 文件到指定目录（去除顶层 go/ 前缀）
 func ExtractZip(zipPath, destPath string) error {
+    return extractZip(zipPath, destPath, ExtractOptions{})
+}
+
+func extractZip(zipPath, destPath string, opts ExtractOptions) error {
     r, err := zip.OpenReader(zipPath)
     if err != nil {
         return fmt.Errorf("failed to open zip: %w", err)
     }
     defer r.Close()
 
-    if err := os.MkdirAll(destPath, 0755); err != nil {
+    modes, err := LoadModes()
+    if err != nil {
+        return err
+    }
+
+    if err := modes.MkdirAll(destPath, 0); err != nil {
         return fmt.Errorf("failed to create destination directory: %w", err)
     }
 
     for _, f := range r.File {
-        name := strings.TrimPrefix(f.Name, "go/")
-        targetPath := filepath.Join(destPath, name)
+        name := opts.trim(f.Name)
+        if opts.excluded(name) {
+            continue
+        }
+        targetPath, err := safeJoin(destPath, name)
+        if err != nil {
+            return err
+        }
 
         if f.FileInfo().IsDir() {
-            if err := os.MkdirAll(targetPath, f.Mode()); err != nil {
+            if err := modes.MkdirAll(targetPath, f.Mode().Perm()); err != nil {
                 return fmt.Errorf("failed to create directory: %w", err)
             }
             continue
         }
 
-        if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+        if err := modes.MkdirAll(filepath.Dir(targetPath), 0); err != nil {
             return fmt.Errorf("failed to create parent directory: %w", err)
         }
 
@@ -277,11 +569,19 @@ func ExtractZip(zipPath, destPath string) error {
             return fmt.Errorf("failed to open zipped file: %w", err)
         }
 
-        out, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode())
+        mode, custom := modes.fileMode(name, int64(f.Mode().Perm()))
+        out, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
         if err != nil {
             rc.Close()
             return fmt.Errorf("failed to create file: %w", err)
         }
+        if custom {
+            if err := out.Chmod(mode); err != nil {
+                rc.Close()
+                out.Close()
+                return fmt.Errorf("failed to set file mode: %w", err)
+            }
+        }
 
         if _, err := io.Copy(out, rc); err != nil {
             rc.Close()
@@ -295,17 +595,55 @@ func ExtractZip(zipPath, destPath string) error {
     return nil
 }
 
-func extractFile(reader *tar.Reader, path string, mode int64) error {
+// toolchainMode 返回文件解压后的权限。在 Windows 上重新打包的 tar 可能丢失可执行位，
+// 因此 bin/ 与 pkg/tool/ 下的文件始终使用 0755，其他文件保留归档中记录的权限。
+func toolchainMode(name string, mode int64) int64 {
+	name = filepath.ToSlash(name)
+	if strings.HasPrefix(name, "bin/") || strings.HasPrefix(name, "pkg/tool/") {
+		return 0755
+	}
+	return mode
+}
+
+// safeJoin 返回归档条目 name 在 destPath 中的解压位置；含有 ../ 等、会写到 destPath 之外的条目（Zip Slip）返回错误
+func safeJoin(destPath, name string) (string, error) {
+	dest := filepath.Clean(destPath)
+	target := filepath.Join(dest, name)
+	if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q would be extracted outside %s", name, destPath)
+	}
+	return target, nil
+}
+
+// extractFile 将 tar 条目写入 path。chmod 为 true 时显式设置权限，使配置的权限不受 umask 影响。
+func extractFile(reader io.Reader, path string, mode os.FileMode, chmod bool) error {
 	// 创建文件
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(mode))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
+	if chmod {
+		if err := file.Chmod(mode); err != nil {
+			return err
+		}
+	}
 
 	// 复制内容
-	_, err = io.Copy(file, reader)
-	return err
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// SyncDir 对目录执行 fsync，确保目录项已持久化（部分平台不支持时忽略错误）
+func SyncDir(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
 }
 
 // ComputeSHA256 计算文件的 SHA256 摘要
@@ -336,6 +674,14 @@ func VerifySHA256(path, expected string) error {
     return nil
 }
 
+// MatchSHA256 比较已计算的摘要与期望值，错误信息与 VerifySHA256 一致
+func MatchSHA256(actual, expected string) error {
+    if !strings.EqualFold(actual, expected) {
+        return fmt.Errorf("sha256 mismatch: expected %s, got %s", expected, actual)
+    }
+    return nil
+}
+
 // GetPlatform 获取当前平台信息
 func GetPlatform() string {
     return fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
@@ -347,21 +693,42 @@ func FileExists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
-// EnsureDir 确保目录存在，如果不存在则创建
+// EnsureDir 确保目录存在，如果不存在则按 GVM_DIR_MODE（默认 0755）创建
 func EnsureDir(path string) error {
     if !FileExists(path) {
-        return os.MkdirAll(path, 0755)
+        modes, err := LoadModes()
+        if err != nil {
+            return err
+        }
+        return modes.MkdirAll(path, 0)
     }
     return nil
 }
 
-// GetHomeDir 获取用户主目录
+var homeFallbackWarning sync.Once
+
+// GetHomeDir 获取用户主目录。无法确定主目录时（如未设置 HOME 的容器）回退到
+// 系统临时目录，并在 stderr 给出一次警告，避免在根目录下创建 /.gvm
 func GetHomeDir() (string, error) {
 	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+	if err == nil && home != "" {
+		return home, nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("$HOME is empty")
 	}
-	return home, nil
+	fallback := os.TempDir()
+	homeFallbackWarning.Do(func() {
+		fmt.Fprintf(os.Stderr, "Warning: cannot determine home directory (%v); using %s. Set HOME to choose where ~/.gvm lives (GVM_HOME only chooses where Go versions are installed)\n", err, fallback)
+	})
+	return fallback, nil
+}
+
+// HomeDir 与 GetHomeDir 相同，用于只需要路径的场景
+func HomeDir() string {
+	home, _ := GetHomeDir()
+	return home
 }
 
 // GetShellConfigFile 获取当前用户的shell配置文件路径
@@ -371,14 +738,12 @@ func GetShellConfigFile() (string, error) {
 		return "", err
 	}
 
-	// 检测当前shell
-	shell := os.Getenv("SHELL")
-	if shell == "" {
-		return "", fmt.Errorf("unable to detect current shell")
+	// 检测当前shell（--shell、父进程、$SHELL），见 DetectShell
+	shellName, source := DetectShell()
+	if shellName == "" {
+		return "", fmt.Errorf("unable to detect current shell; pass --shell bash, zsh or fish")
 	}
 
-	shellName := filepath.Base(shell)
-
 	switch shellName {
 	case "bash":
 		bashrc := filepath.Join(home, ".bashrc")
@@ -389,46 +754,197 @@ func GetShellConfigFile() (string, error) {
 	case "zsh":
 		return filepath.Join(home, ".zshrc"), nil
 	case "fish":
-		return filepath.Join(home, ".config", "fish", "config.fish"), nil
+		// conf.d 中的文件在启动时自动加载，不需要修改用户的 config.fish
+		return filepath.Join(home, ".config", "fish", "conf.d", "gvm.fish"), nil
 	default:
-		return "", fmt.Errorf("unsupported shell: %s", shellName)
+		return "", fmt.Errorf("unsupported shell: %s (detected from %s); pass --shell bash, zsh or fish", shellName, source)
 	}
 }
 
-// UpdatePathInShellConfig 更新shell配置文件中的PATH
+// shell 配置文件中由 gvm 管理的代码块标记
+const (
+	shellBlockBegin = "# >>> gvm >>>"
+	shellBlockEnd   = "# <<< gvm <<<"
+)
+
+// UpdatePathInShellConfig 更新shell配置文件中的PATH。
+// PATH 设置写入由 gvm 管理的代码块中，重复调用时内容不变则不会改写文件。
 func UpdatePathInShellConfig(goBinPath string) error {
+	_, err := EnsureShellPath(goBinPath)
+	return err
+}
+
+// EnsureShellPath 确保 shell 配置文件中存在将 binPath 前置到 PATH 的管理代码块，
+// 返回被修改的配置文件路径（未修改时返回空字符串）
+func EnsureShellPath(binPath string) (string, error) {
 	configFile, err := GetShellConfigFile()
 	if err != nil {
-		return err
+		return "", err
+	}
+	shell, _ := DetectShell()
+	fish := shell == "fish"
+	if fish {
+		// 旧版本在 config.fish 中写入了 fish 无法识别的 export 语句，迁移到 conf.d 时一并删除
+		if err := removeShellBlock(filepath.Join(filepath.Dir(filepath.Dir(configFile)), "config.fish")); err != nil {
+			return "", err
+		}
 	}
 
-	// 读取现有内容
+	// 读取现有内容（文件不存在时视为空）
 	content, err := os.ReadFile(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to read shell config: %w", err)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read shell config: %w", err)
 	}
 
-	lines := strings.Split(string(content), "\n")
-	newLines := []string{}
-
-	// 移除旧的GVM PATH设置
-	for _, line := range lines {
-		if !strings.Contains(line, "# GVM PATH") && !strings.Contains(line, ".gvm/versions") {
-			newLines = append(newLines, line)
-		}
-	}
+	// 移除旧的GVM PATH设置与已有的管理代码块
+	newLines := stripShellBlock(string(content))
 
 	// 添加新的PATH设置
-    exportLine := fmt.Sprintf("export PATH=\"%s:$PATH\" # GVM PATH", goBinPath)
-    newLines = append(newLines, exportLine)
+	pathLine := fmt.Sprintf("export PATH=\"%s:$PATH\"", binPath)
+	if fish {
+		// 重复加载（例如 source 后再开子 shell）时不重复添加
+		pathLine = fmt.Sprintf("contains -- \"%s\" $PATH; or set -gx PATH \"%s\" $PATH", binPath, binPath)
+	}
+	if len(newLines) > 0 {
+		newLines = append(newLines, "")
+	}
+	newLines = append(newLines,
+		shellBlockBegin,
+		pathLine,
+		shellBlockEnd,
+		"")
 
 	// 写回文件
 	newContent := strings.Join(newLines, "\n")
+	if newContent == string(content) {
+		return "", nil
+	}
+	if err := EnsureDir(filepath.Dir(configFile)); err != nil {
+		return "", fmt.Errorf("failed to create shell config directory: %w", err)
+	}
+	if err := os.WriteFile(configFile, []byte(newContent), 0644); err != nil {
+		return "", fmt.Errorf("failed to update shell config: %w", err)
+	}
+
+	return configFile, nil
+}
+
+// stripShellBlock 返回去除 gvm 管理代码块与旧版 GVM PATH 设置后的各行，并去掉末尾的空行
+func stripShellBlock(content string) []string {
+	newLines := []string{}
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == shellBlockBegin:
+			inBlock = true
+		case trimmed == shellBlockEnd:
+			inBlock = false
+		case inBlock:
+		case strings.Contains(line, "# GVM PATH") || strings.Contains(line, ".gvm/versions"):
+		default:
+			newLines = append(newLines, line)
+		}
+	}
+	for len(newLines) > 0 && strings.TrimSpace(newLines[len(newLines)-1]) == "" {
+		newLines = newLines[:len(newLines)-1]
+	}
+	return newLines
+}
+
+// removeShellBlock 从 configFile 中删除 gvm 管理代码块；文件不存在或没有代码块时不做修改
+func removeShellBlock(configFile string) error {
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read shell config: %w", err)
+	}
+	if !strings.Contains(string(content), shellBlockBegin) && !strings.Contains(string(content), "# GVM PATH") {
+		return nil
+	}
+	newContent := strings.Join(stripShellBlock(string(content)), "\n")
+	if newContent != "" {
+		newContent += "\n"
+	}
 	if err := os.WriteFile(configFile, []byte(newContent), 0644); err != nil {
 		return fmt.Errorf("failed to update shell config: %w", err)
 	}
+	return nil
+}
 
-    return nil
+// ActivationHint 返回让当前终端立即生效所需执行的命令提示
+func ActivationHint() string {
+	if runtime.GOOS == "windows" {
+		home, _ := GetHomeDir()
+		return fmt.Sprintf("run '%s' in PowerShell (or 'call \"%s\"' in cmd), or open a new terminal",
+			ActivationCommand(), filepath.Join(home, ".gvm", "env.bat"))
+	}
+	cmd := ActivationCommand()
+	if cmd == "" {
+		return "open a new terminal"
+	}
+	return fmt.Sprintf("run '%s' or open a new terminal", cmd)
+}
+
+// ActivationCommand 返回在当前 shell 中重新加载 gvm PATH 设置的命令，例如 source ~/.zshrc；
+// Windows 上为 PowerShell 的 . "~/.gvm/env.ps1"（在 cmd 中为 call "~/.gvm/env.bat"）。无法识别当前 shell 时返回空字符串。
+func ActivationCommand() string {
+	if runtime.GOOS == "windows" {
+		home, _ := GetHomeDir()
+		if shell, _ := DetectShell(); shell == "cmd" {
+			return fmt.Sprintf("call \"%s\"", filepath.Join(home, ".gvm", "env.bat"))
+		}
+		return fmt.Sprintf(". \"%s\"", filepath.Join(home, ".gvm", "env.ps1"))
+	}
+	configFile, err := GetShellConfigFile()
+	if err != nil {
+		return ""
+	}
+	return "source " + configFile
+}
+
+// PathContains 检查 PATH 环境变量中是否包含指定目录
+func PathContains(dir string) bool {
+	for _, p := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(p) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// PathWithin 判断 path 是否为 root 或其子路径。两者先规范化，Windows 上不区分大小写，
+// 并把 / 视同 \（不同 API 返回的盘符大小写与分隔符可能不一致，如 C:\Users 与 c:/users）。
+func PathWithin(path, root string) bool {
+	return PathWithinOS(path, root, runtime.GOOS)
+}
+
+// PathWithinOS 与 PathWithin 相同，但按 goos 指定的系统规则比较，便于在其他平台上测试 Windows 路径
+func PathWithinOS(path, root, goos string) bool {
+	path, root = normalizePath(path, goos), normalizePath(root, goos)
+	if path == root {
+		return true
+	}
+	sep := "/"
+	if goos == "windows" {
+		sep = `\`
+	}
+	if !strings.HasSuffix(root, sep) {
+		root += sep
+	}
+	return strings.HasPrefix(path, root)
+}
+
+// normalizePath 按 goos 的规则清理路径；Windows 路径统一为 \ 分隔与小写
+func normalizePath(p, goos string) string {
+	if goos != "windows" {
+		return filepath.Clean(p)
+	}
+	// 非 Windows 平台上 filepath.Clean 不识别 \，统一换成 / 清理后再换回
+	p = filepath.Clean(strings.ReplaceAll(p, `\`, "/"))
+	return strings.ToLower(strings.ReplaceAll(p, "/", `\`))
 }
 
 // UpdatePathForWindows 使用 PowerShell profile 加载 ~/.gvm/env.ps1 以更新 PATH
@@ -485,6 +1001,11 @@ func GetShimsDir() (string, error) {
 
 // UpdateShims 更新 go 可执行的 shim 以指向指定版本的 go 二进制
 func UpdateShims(goBinPath string) error {
+    return WriteNamedShim("go", goBinPath)
+}
+
+// WriteNamedShim 在 shims 目录创建名为 name 的 shim，调用 goBinPath 下的 go 二进制
+func WriteNamedShim(name, goBinPath string) error {
     shimsDir, err := GetShimsDir()
     if err != nil {
         return err
@@ -494,24 +1015,130 @@ func UpdateShims(goBinPath string) error {
     }
 
     if runtime.GOOS == "windows" {
-        // 生成 go.cmd 调用选定版本的 go.exe
+        // 生成 <name>.cmd 调用选定版本的 go.exe
         target := filepath.Join(goBinPath, "go.exe")
-        cmdPath := filepath.Join(shimsDir, "go.cmd")
+        cmdPath := filepath.Join(shimsDir, name+".cmd")
         content := fmt.Sprintf("@echo off\r\n\"%s\" %%*\r\n", target)
         if err := os.WriteFile(cmdPath, []byte(content), 0644); err != nil {
-            return fmt.Errorf("failed to write shim go.cmd: %w", err)
+            return fmt.Errorf("failed to write shim %s.cmd: %w", name, err)
         }
     } else {
-        // Unix: 创建/更新符号链接 ~/.gvm/shims/go -> <install>/bin/go
+        // Unix: 创建/更新符号链接 ~/.gvm/shims/<name> -> <install>/bin/go
         target := filepath.Join(goBinPath, "go")
-        linkPath := filepath.Join(shimsDir, "go")
-        if FileExists(linkPath) {
+        linkPath := filepath.Join(shimsDir, name)
+        if _, err := os.Lstat(linkPath); err == nil {
             _ = os.Remove(linkPath)
         }
         if err := os.Symlink(target, linkPath); err != nil {
-            return fmt.Errorf("failed to create go shim symlink: %w", err)
+            return fmt.Errorf("failed to create %s shim symlink: %w", name, err)
         }
     }
 
     return nil
 }
+
+// RemoveNamedShim 删除 shims 目录中名为 name 的 shim
+func RemoveNamedShim(name string) error {
+    shimsDir, err := GetShimsDir()
+    if err != nil {
+        return err
+    }
+    shimPath := filepath.Join(shimsDir, name)
+    if runtime.GOOS == "windows" {
+        shimPath += ".cmd"
+    }
+    if err := os.Remove(shimPath); err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("failed to remove shim %s: %w", name, err)
+    }
+    return nil
+}
+
+// GetStableRootPath 返回固定 GOROOT 符号链接 ~/.gvm/go 的路径
+func GetStableRootPath() (string, error) {
+    home, err := GetHomeDir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(home, ".gvm", "go"), nil
+}
+
+// UpdateStableRoot 将 ~/.gvm/go 指向 target。先在旁边创建临时链接再重命名覆盖，
+// 切换过程中链接始终有效
+func UpdateStableRoot(target string) error {
+    linkPath, err := GetStableRootPath()
+    if err != nil {
+        return err
+    }
+    if err := EnsureDir(filepath.Dir(linkPath)); err != nil {
+        return err
+    }
+    if fi, err := os.Lstat(linkPath); err == nil && fi.Mode()&os.ModeSymlink == 0 {
+        return fmt.Errorf("%s exists and is not a symlink; remove it to enable stable-root", linkPath)
+    }
+
+    tmpPath := linkPath + ".tmp"
+    _ = os.Remove(tmpPath)
+    if err := os.Symlink(target, tmpPath); err != nil {
+        return fmt.Errorf("failed to create stable root symlink: %w", err)
+    }
+    if err := os.Rename(tmpPath, linkPath); err != nil {
+        os.Remove(tmpPath)
+        return fmt.Errorf("failed to update stable root symlink: %w", err)
+    }
+    return nil
+}
+
+// RemoveStableRoot 删除 ~/.gvm/go 符号链接；不会删除同名的普通目录
+func RemoveStableRoot() error {
+    linkPath, err := GetStableRootPath()
+    if err != nil {
+        return err
+    }
+    fi, err := os.Lstat(linkPath)
+    if os.IsNotExist(err) {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    if fi.Mode()&os.ModeSymlink == 0 {
+        return fmt.Errorf("%s is not a symlink; leaving it in place", linkPath)
+    }
+    if err := os.Remove(linkPath); err != nil {
+        return fmt.Errorf("failed to remove stable root symlink: %w", err)
+    }
+    return nil
+}
+
+// ShimPath 返回名为 name 的 shim 文件路径（Windows 上为 <name>.cmd）
+func ShimPath(name string) (string, error) {
+    shimsDir, err := GetShimsDir()
+    if err != nil {
+        return "", err
+    }
+    if runtime.GOOS == "windows" {
+        name += ".cmd"
+    }
+    return filepath.Join(shimsDir, name), nil
+}
+
+// ShimTarget 返回名为 name 的 shim 当前调用的 go 二进制路径
+func ShimTarget(name string) (string, error) {
+    shimPath, err := ShimPath(name)
+    if err != nil {
+        return "", err
+    }
+    if runtime.GOOS == "windows" {
+        data, err := os.ReadFile(shimPath)
+        if err != nil {
+            return "", err
+        }
+        // 内容形如 @echo off\r\n"<target>" %*
+        parts := strings.Split(string(data), "\"")
+        if len(parts) < 3 {
+            return "", fmt.Errorf("malformed shim %s.cmd", name)
+        }
+        return parts[1], nil
+    }
+    return os.Readlink(shimPath)
+}
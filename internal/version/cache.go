@@ -0,0 +1,89 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// VersionsCacheTTL 是版本列表缓存的有效期
+const VersionsCacheTTL = time.Hour
+
+// versionsCachePath 返回版本列表缓存文件路径（~/.gvm/cache/versions.json）
+func versionsCachePath() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "versions.json"), nil
+}
+
+// LoadVersionsCache 读取版本列表缓存，返回缓存内容与写入时间。
+// 缓存不存在、已过期或无法解析（例如写入中断导致的截断文件）时视为未命中，
+// 无法解析的缓存文件会被删除，调用方应重新从网络获取。
+func LoadVersionsCache(maxAge time.Duration) ([]GoVersion, time.Time, bool) {
+	path, err := versionsCachePath()
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	if maxAge > 0 && time.Since(info.ModTime()) > maxAge {
+		return nil, time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var versions []GoVersion
+	if err := json.Unmarshal(data, &versions); err != nil || len(versions) == 0 {
+		_ = os.Remove(path)
+		return nil, time.Time{}, false
+	}
+	return versions, info.ModTime(), true
+}
+
+// SaveVersionsCache 原子地写入版本列表缓存：先写临时文件再重命名，
+// 保证进程中途退出时不会留下截断的缓存文件。
+func SaveVersionsCache(versions []GoVersion) error {
+	path, err := versionsCachePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(versions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal versions cache: %w", err)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "versions-*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to flush cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	return nil
+}
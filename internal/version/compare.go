@@ -0,0 +1,94 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parsedVersion 是拆分后的 Go 版本号
+type parsedVersion struct {
+	major, minor, patch int
+	pre                 int // 预发布阶段：0 beta、1 rc、2 正式版
+	preNum              int // 预发布序号，例如 rc2 中的 2
+}
+
+// parseVersion 解析 go1.21.5、go1.22rc1、go1.21beta2、1.20 等形式的版本号
+func parseVersion(v string) parsedVersion {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "go")
+	p := parsedVersion{pre: 2}
+
+	// 拆出预发布后缀
+	for _, tag := range []struct {
+		name  string
+		stage int
+	}{{"beta", 0}, {"rc", 1}} {
+		if i := strings.Index(v, tag.name); i >= 0 {
+			p.pre = tag.stage
+			p.preNum, _ = strconv.Atoi(v[i+len(tag.name):])
+			v = v[:i]
+			break
+		}
+	}
+
+	parts := strings.Split(v, ".")
+	nums := []*int{&p.major, &p.minor, &p.patch}
+	for i := 0; i < len(parts) && i < len(nums); i++ {
+		*nums[i], _ = strconv.Atoi(parts[i])
+	}
+	return p
+}
+
+// CompareVersions 按语义比较两个 Go 版本号，a<b 返回 -1，a==b 返回 0，a>b 返回 1。
+// 预发布版本小于同系列的正式版本（go1.22rc1 < go1.22.0），go1.20 与 go1.20.0 相等。
+func CompareVersions(a, b string) int {
+	pa, pb := parseVersion(a), parseVersion(b)
+	for _, d := range [][2]int{
+		{pa.major, pb.major},
+		{pa.minor, pb.minor},
+		{pa.patch, pb.patch},
+		{pa.pre, pb.pre},
+		{pa.preNum, pb.preNum},
+	} {
+		if d[0] < d[1] {
+			return -1
+		}
+		if d[0] > d[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// SemVer 是解析后的 Go 版本号，供 gvm semver 输出
+type SemVer struct {
+	Version    string `json:"version"` // 规范形式，例如 go1.22rc1
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+	Patch      int    `json:"patch"`
+	Prerelease string `json:"prerelease,omitempty"` // 例如 rc1、beta2
+	Series     string `json:"series"`               // 例如 go1.22
+}
+
+var semverPattern = regexp.MustCompile(`^(?:go)?(\d+)(?:\.(\d+))?(?:\.(\d+))?((?:beta|rc)\d+)?$`)
+
+// ParseSemVer 严格解析 Go 版本号（go1.21.5、1.22rc1、go1.21beta2、1.20 等），
+// 与 CompareVersions 使用相同的规则；无法识别时返回错误。
+func ParseSemVer(v string) (SemVer, error) {
+	v = strings.TrimSpace(v)
+	m := semverPattern.FindStringSubmatch(v)
+	if m == nil {
+		return SemVer{}, fmt.Errorf("invalid Go version %q: expected a form like go1.21.5, 1.22rc1 or go1.21beta2", v)
+	}
+	p := parseVersion(v)
+	s := SemVer{
+		Version:    "go" + strings.TrimPrefix(v, "go"),
+		Major:      p.major,
+		Minor:      p.minor,
+		Patch:      p.patch,
+		Prerelease: m[4],
+	}
+	s.Series = fmt.Sprintf("go%d.%d", s.Major, s.Minor)
+	return s, nil
+}
@@ -0,0 +1,136 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// DownloadResult 记录 DownloadVersion 中单个安装包的下载结果
+type DownloadResult struct {
+	Filename string
+	Size     int
+	Err      error
+}
+
+// DownloadVersion 将指定版本的安装包下载到 dir 并校验 SHA256，用于为离线镜像准备文件。
+// allPlatforms 为 false 时只下载当前平台的安装包；使用最多 jobs 个并发，结果顺序与
+// 版本 JSON 中的文件顺序一致。全部完成后在 dir 中写入成功文件的 SHA256SUMS，以及
+// 与 go.dev/dl/?mode=json 格式相同、只包含该版本的 version.json。
+func (vm *VersionManager) DownloadVersion(version, dir string, allPlatforms bool, jobs int) ([]DownloadResult, error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	availableVersions, err := vm.GetAvailableVersions()
+	if err != nil {
+		return nil, err
+	}
+	var target *GoVersion
+	for i := range availableVersions {
+		if availableVersions[i].Version == version {
+			target = &availableVersions[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, newError(CodeVersionNotFound, "version %s not found in available versions", version)
+	}
+
+	// 只下载当前平台时与 install 选择同一个安装包
+	var files []GoFile
+	if allPlatforms {
+		for _, f := range target.Files {
+			if f.IsArchive() {
+				files = append(files, f)
+			}
+		}
+	} else if f := vm.archiveFor(target); f != nil {
+		files = append(files, *f)
+	}
+	if len(files) == 0 {
+		return nil, newError(CodeUnsupportedPlatform, "no package found for %s-%s; %s provides: %s",
+			runtime.GOOS, runtime.GOARCH, version, strings.Join(supportedPlatforms(target), ", "))
+	}
+
+	if vm.settings.VerifyOfficial != config.VerifyOfficialOff {
+		if err := vm.useOfficialChecksums(version, files); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := utils.EnsureDir(dir); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	providers, err := vm.downloadProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DownloadResult, len(files))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, f := range files {
+		results[i] = DownloadResult{Filename: f.Filename, Size: f.Size}
+		wg.Add(1)
+		go func(i int, f GoFile) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i].Err = vm.downloadVerified(providers, f, filepath.Join(dir, f.Filename))
+		}(i, f)
+	}
+	wg.Wait()
+
+	// 只为下载并校验成功的文件生成 SHA256SUMS 与 version.json
+	subset := *target
+	subset.Files = nil
+	var sums []string
+	for i, r := range results {
+		if r.Err == nil {
+			subset.Files = append(subset.Files, files[i])
+			sums = append(sums, fmt.Sprintf("%s  %s\n", files[i].SHA256, files[i].Filename))
+		}
+	}
+	if len(subset.Files) == 0 {
+		return results, nil
+	}
+	sort.Strings(sums)
+	if err := os.WriteFile(filepath.Join(dir, "SHA256SUMS"), []byte(strings.Join(sums, "")), 0644); err != nil {
+		return results, fmt.Errorf("failed to write SHA256SUMS: %w", err)
+	}
+	data, err := json.MarshalIndent([]GoVersion{subset}, "", "  ")
+	if err != nil {
+		return results, fmt.Errorf("failed to marshal version.json: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), append(data, '\n'), 0644); err != nil {
+		return results, fmt.Errorf("failed to write version.json: %w", err)
+	}
+	return results, nil
+}
+
+// downloadVerified 下载单个安装包并校验 SHA256；目标文件已存在且校验通过时跳过下载
+func (vm *VersionManager) downloadVerified(providers []MirrorProvider, f GoFile, dest string) error {
+	if f.SHA256 != "" && utils.FileExists(dest) && utils.VerifySHA256(dest, f.SHA256) == nil {
+		return nil
+	}
+	sum, url, err := vm.downloadFromProviders(providers, f, dest, fetchOptions{resume: true, parts: 1})
+	if err != nil {
+		return phaseError(ErrDownload, CodeDownloadFailed, "failed to download %s: %v", f.Filename, err)
+	}
+	if f.SHA256 == "" {
+		return nil
+	}
+	if err := utils.MatchSHA256(sum, f.SHA256); err != nil {
+		quarantineMismatch(dest, QuarantineRecord{File: f.Filename, URL: url, Expected: f.SHA256, Actual: sum})
+		return phaseError(ErrChecksum, CodeChecksumMismatch, "failed to verify %s: %w", f.Filename, err)
+	}
+	return nil
+}
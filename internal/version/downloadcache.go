@@ -0,0 +1,170 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// DefaultCacheMaxSize 是下载缓存的默认容量上限（2GB），可通过 GVM_CACHE_MAX_SIZE 覆盖
+const DefaultCacheMaxSize int64 = 2 << 30
+
+// CachedArchive 描述下载缓存中的一个安装包
+type CachedArchive struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"last_used"`
+	Partial  bool      `json:"partial,omitempty"` // 未完成的断点续传文件
+}
+
+// CacheDir 返回缓存根目录（~/.gvm/cache），包含版本列表缓存与下载缓存
+func CacheDir() (string, error) {
+	homeDir, err := utils.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".gvm", "cache"), nil
+}
+
+// downloadCacheDir 返回安装包下载缓存目录（~/.gvm/cache/downloads）
+func downloadCacheDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "downloads"), nil
+}
+
+// CacheMaxSize 返回下载缓存容量上限。GVM_CACHE_MAX_SIZE 支持纯字节数或
+// K/M/G 后缀（如 500M、2GB）；设置为 0 时不缓存安装包。
+func CacheMaxSize() int64 {
+	v := strings.TrimSpace(os.Getenv("GVM_CACHE_MAX_SIZE"))
+	if v == "" {
+		return DefaultCacheMaxSize
+	}
+	size, err := ParseSize(v)
+	if err != nil {
+		return DefaultCacheMaxSize
+	}
+	return size
+}
+
+// ParseSize 解析带可选单位（K、M、G，可带 B 或 iB）的大小字符串
+func ParseSize(s string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	upper = strings.TrimSuffix(strings.TrimSuffix(upper, "IB"), "B")
+	mult := int64(1)
+	switch {
+	case strings.HasSuffix(upper, "K"):
+		mult = 1 << 10
+	case strings.HasSuffix(upper, "M"):
+		mult = 1 << 20
+	case strings.HasSuffix(upper, "G"):
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		upper = upper[:len(upper)-1]
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+// ListDownloadCache 返回下载缓存中的文件，按最近使用时间从新到旧排序
+func ListDownloadCache() ([]CachedArchive, error) {
+	dir, err := downloadCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read download cache: %w", err)
+	}
+	var archives []CachedArchive
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		name := e.Name()
+		archives = append(archives, CachedArchive{
+			Name:     name,
+			Size:     info.Size(),
+			LastUsed: info.ModTime(),
+			Partial:  strings.HasSuffix(name, ".part") || strings.HasSuffix(name, ".meta"),
+		})
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].LastUsed.After(archives[j].LastUsed)
+	})
+	return archives, nil
+}
+
+// evictDownloadCache 删除最久未使用的安装包，直到加入 incoming 字节后缓存不超过上限。
+// 未完成的断点续传文件不会被淘汰。
+func evictDownloadCache(incoming int64) error {
+	archives, err := ListDownloadCache()
+	if err != nil {
+		return err
+	}
+	dir, err := downloadCacheDir()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, a := range archives {
+		total += a.Size
+	}
+	limit := CacheMaxSize()
+	// 从最旧的开始删除
+	for i := len(archives) - 1; i >= 0 && total+incoming > limit; i-- {
+		a := archives[i]
+		if a.Partial {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, a.Name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict %s from cache: %w", a.Name, err)
+		}
+		total -= a.Size
+	}
+	return nil
+}
+
+// touchCachedArchive 更新安装包的修改时间，作为最近使用时间参与淘汰
+func touchCachedArchive(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// CleanCache 删除整个缓存目录（安装包与版本列表缓存），返回释放的字节数
+func CleanCache() (int64, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return 0, err
+	}
+	var freed int64
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			freed += info.Size()
+		}
+		return nil
+	})
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to remove cache directory: %w", err)
+	}
+	return freed, nil
+}
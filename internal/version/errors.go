@@ -0,0 +1,70 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 错误码，供脚本通过 JSON 错误输出区分错误类型
+const (
+	CodeUnknown             = "error"
+	CodeVersionNotInstalled = "version_not_installed"
+	CodeAlreadyInstalled    = "version_already_installed"
+	CodeVersionNotFound     = "version_not_found"
+	CodeUnsupportedPlatform = "unsupported_platform"
+	CodeVersionInUse        = "version_in_use"
+	CodeDownloadFailed      = "download_failed"
+	CodeChecksumMismatch    = "checksum_mismatch"
+	CodeExtractFailed       = "extract_failed"
+	CodeValidateFailed      = "validation_failed"
+	CodeForeignInstall      = "foreign_go_install"
+	CodeNotWritable         = "not_writable"
+)
+
+// 安装阶段错误，调用方可通过 errors.Is 判断失败发生在哪个阶段以决定是否重试：
+// 下载失败通常可以重试或更换镜像，其余阶段重试一般无济于事。
+var (
+	ErrDownload = errors.New("download failed")
+	ErrChecksum = errors.New("checksum mismatch")
+	ErrExtract  = errors.New("extraction failed")
+	ErrValidate = errors.New("validation failed")
+)
+
+// Error 是带错误码的版本管理错误。
+type Error struct {
+	Code  string // 错误码，例如 version_not_installed
+	Phase error  // 安装阶段错误（ErrDownload 等），可为 nil
+	Err   error  // 原始错误
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrDownload) 等判断对带阶段的错误成立
+func (e *Error) Is(target error) bool {
+	return e.Phase != nil && target == e.Phase
+}
+
+// newError 创建带错误码的错误，格式化规则同 fmt.Errorf
+func newError(code, format string, args ...interface{}) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// ErrorCode 返回错误链中第一个带错误码的错误的错误码，没有时返回 CodeUnknown。
+func ErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeUnknown
+}
+
+// phaseError 创建带安装阶段与错误码的错误
+func phaseError(phase error, code, format string, args ...interface{}) error {
+	return &Error{Code: code, Phase: phase, Err: fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,72 @@
+package version
+
+import (
+	"debug/buildinfo"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// GOBINTool 描述 GOBIN 中一个由 `go install` 安装的工具。
+type GOBINTool struct {
+	Name      string `json:"name"`       // 文件名
+	Path      string `json:"path"`       // 模块内的包路径，例如 golang.org/x/tools/gopls
+	GoVersion string `json:"go_version"` // 构建该工具的 Go 版本
+}
+
+// GetGOBIN 返回 `go install` 的目标目录：GOBIN，否则为 GOPATH 第一项下的 bin，否则为 ~/go/bin。
+func GetGOBIN() string {
+	if gobin := strings.TrimSpace(os.Getenv("GOBIN")); gobin != "" {
+		return gobin
+	}
+	if gopath := strings.TrimSpace(os.Getenv("GOPATH")); gopath != "" {
+		return filepath.Join(filepath.SplitList(gopath)[0], "bin")
+	}
+	homeDir := utils.HomeDir()
+	return filepath.Join(homeDir, "go", "bin")
+}
+
+// ListGOBINTools 读取 GOBIN 中各可执行文件的构建信息，非 Go 构建的文件会被跳过。
+func ListGOBINTools(gobin string) ([]GOBINTool, error) {
+	entries, err := os.ReadDir(gobin)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	tools := []GOBINTool{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := buildinfo.ReadFile(filepath.Join(gobin, entry.Name()))
+		if err != nil {
+			continue
+		}
+		tools = append(tools, GOBINTool{
+			Name:      entry.Name(),
+			Path:      info.Path,
+			GoVersion: info.GoVersion,
+		})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+// GOBINConflict 检查 GOBIN 是否位于 gvm 安装目录中另一个版本的目录树内，
+// 返回冲突的版本号（无冲突时为空字符串）。
+func (vm *VersionManager) GOBINConflict(gobin, active string) string {
+	rel, err := filepath.Rel(vm.installDir, gobin)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	owner := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+	if owner == active {
+		return ""
+	}
+	return owner
+}
@@ -0,0 +1,148 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GoEnvMinVersion 是开始读取 $GOROOT/go.env 的 Go 版本，更早的版本会忽略该文件
+const GoEnvMinVersion = "go1.21"
+
+// GoEnvEntry 是 go.env 中的一项设置
+type GoEnvEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// readOnlyGoEnv 是 `go env -json` 会列出但不能写入配置文件的变量
+var readOnlyGoEnv = map[string]bool{
+	"GOENV": true, "GOEXE": true, "GOGCCFLAGS": true, "GOHOSTARCH": true, "GOHOSTOS": true,
+	"GOMOD": true, "GOROOT": true, "GOTELEMETRY": true, "GOTELEMETRYDIR": true,
+	"GOTOOLDIR": true, "GOVERSION": true, "GOWORK": true,
+}
+
+// GetGoEnv 返回指定版本 $GOROOT/go.env 中的设置（按文件中的顺序，忽略注释与空行）
+func (vm *VersionManager) GetGoEnv(version string) ([]GoEnvEntry, error) {
+	if err := vm.requireInstalled(version); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(vm.goEnvPath(version))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []GoEnvEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read go.env: %w", err)
+	}
+	entries := []GoEnvEntry{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := parseGoEnvLine(line); ok {
+			entries = append(entries, GoEnvEntry{Key: key, Value: value})
+		}
+	}
+	return entries, nil
+}
+
+// SetGoEnv 在指定版本的 $GOROOT/go.env 中设置 key=value（文件不存在时创建），
+// 已有的同名设置会被替换，注释与其他行保持不变；value 为空时删除该设置。
+// key 会与该版本 `go env -json` 列出的变量名比对，无法运行该版本时跳过校验。
+func (vm *VersionManager) SetGoEnv(version, key, value string) error {
+	if err := vm.requireInstalled(version); err != nil {
+		return err
+	}
+	if err := vm.validateGoEnvKey(version, key); err != nil {
+		return err
+	}
+
+	path := vm.goEnvPath(version)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read go.env: %w", err)
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	replaced := false
+	result := make([]string, 0, len(lines)+1)
+	for _, line := range lines {
+		if k, _, ok := parseGoEnvLine(line); ok && k == key {
+			if !replaced && value != "" {
+				result = append(result, key+"="+value)
+			}
+			replaced = true
+			continue
+		}
+		result = append(result, line)
+	}
+	if !replaced && value != "" {
+		result = append(result, key+"="+value)
+	}
+
+	content := strings.Join(result, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write go.env: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write go.env: %w", err)
+	}
+	return nil
+}
+
+// validateGoEnvKey 检查 key 是否为该版本可以写入配置文件的 go env 变量
+func (vm *VersionManager) validateGoEnvKey(version, key string) error {
+	if key == "" || strings.ContainsAny(key, "= \t#") {
+		return fmt.Errorf("invalid go env name %q", key)
+	}
+	if readOnlyGoEnv[key] {
+		return fmt.Errorf("%s cannot be set in go.env", key)
+	}
+	out, err := exec.Command(goBinary(filepath.Join(vm.installDir, version)), "env", "-json").Output()
+	if err != nil {
+		return nil
+	}
+	var known map[string]string
+	if json.Unmarshal(out, &known) != nil {
+		return nil
+	}
+	if _, ok := known[key]; !ok {
+		return fmt.Errorf("unknown go env name %q for Go %s", key, version)
+	}
+	return nil
+}
+
+// requireInstalled 在版本未安装时返回 CodeVersionNotInstalled 错误
+func (vm *VersionManager) requireInstalled(version string) error {
+	installed, err := vm.IsVersionInstalled(version)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return newError(CodeVersionNotInstalled, "version %s is not installed", version)
+	}
+	return nil
+}
+
+// goEnvPath 返回指定版本的 go.env 路径
+func (vm *VersionManager) goEnvPath(version string) string {
+	return filepath.Join(vm.installDir, version, "go.env")
+}
+
+// parseGoEnvLine 解析 go.env 中的一行，注释与空行返回 ok=false
+func parseGoEnvLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(line, "=")
+	return strings.TrimSpace(key), value, ok
+}
@@ -0,0 +1,91 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GoModFileName 是 Go 模块文件名
+const GoModFileName = "go.mod"
+
+// ParseGoMod 解析 go.mod 中的 go 与 toolchain 指令，返回对应的 Go 版本号（如 go1.21.5）。
+// 存在 toolchain 指令时优先使用；否则由 go 指令推导。
+func ParseGoMod(path string) (string, error) {
+	goDirective, toolchain, err := readGoMod(path)
+	if err != nil {
+		return "", err
+	}
+	if toolchain != "" {
+		return toolchain, nil
+	}
+	return GoDirectiveToVersion(goDirective), nil
+}
+
+// ResolveGoMod 读取 go.mod 并确定要安装或使用的版本：toolchain 指令与带补丁号的 go 指令原样使用，
+// 只有主次版本号的 go 指令（如 go 1.21）解析为该系列的补丁版本：preferInstalled 为 true 时
+// 优先使用已安装的（见 ResolveSeries），否则使用可用的最新补丁版本（见 ResolveSeriesAvailable）
+func (vm *VersionManager) ResolveGoMod(path string, preferInstalled bool) (string, error) {
+	goDirective, toolchain, err := readGoMod(path)
+	if err != nil {
+		return "", err
+	}
+	if toolchain != "" {
+		return toolchain, nil
+	}
+	if v, series, err := parseVersionSpec(goDirective); err == nil && series {
+		return vm.resolveSeries(v, preferInstalled)
+	}
+	return GoDirectiveToVersion(goDirective), nil
+}
+
+// readGoMod 返回 go.mod 中 go 指令的值与去除后缀的 toolchain 指令（toolchain default 视为未指定）
+func readGoMod(path string) (goDirective, toolchain string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		// 去除行尾注释
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "go":
+			goDirective = fields[1]
+		case "toolchain":
+			toolchain = fields[1]
+		}
+	}
+
+	if toolchain != "" && toolchain != "default" {
+		// toolchain 可能带有后缀，例如 go1.21.5+auto
+		if i := strings.IndexAny(toolchain, "+-"); i > 0 {
+			toolchain = toolchain[:i]
+		}
+		return "", toolchain, nil
+	}
+	if goDirective == "" {
+		return "", "", fmt.Errorf("no go or toolchain directive found in %s", path)
+	}
+	return goDirective, "", nil
+}
+
+// GoDirectiveToVersion 将 go.mod 的 go 指令转换为发布版本号。
+// Go 1.21 起首个发布版本为 go1.N.0，之前为 go1.N。
+func GoDirectiveToVersion(directive string) string {
+	v := strings.TrimPrefix(directive, "go")
+	parts := strings.Split(v, ".")
+	if len(parts) == 2 {
+		if minor, err := strconv.Atoi(parts[1]); err == nil && parts[0] == "1" && minor >= 21 {
+			v += ".0"
+		}
+	}
+	return "go" + v
+}
@@ -0,0 +1,135 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// ToolchainModule 是 Go 以模块形式发布工具链时使用的模块路径
+const ToolchainModule = "golang.org/toolchain"
+
+// defaultGoProxy 与 defaultSumDB 是 GOPROXY、GOSUMDB 未设置时 go 命令使用的默认值
+const (
+	defaultGoProxy = "https://proxy.golang.org"
+	defaultSumDB   = "sum.golang.org"
+)
+
+// toolchainModuleVersion 返回工具链模块的版本，例如 v0.0.1-go1.21.0.linux-amd64
+func toolchainModuleVersion(version, goos, goarch string) string {
+	return fmt.Sprintf("v0.0.1-%s.%s-%s", version, goos, goarch)
+}
+
+// goProxies 解析 GOPROXY 中的代理地址，忽略 direct 与 off
+func goProxies() ([]string, error) {
+	raw := strings.TrimSpace(os.Getenv("GOPROXY"))
+	if raw == "" {
+		raw = defaultGoProxy
+	}
+	var proxies []string
+	for _, p := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == '|' }) {
+		p = strings.TrimRight(strings.TrimSpace(p), "/")
+		if p == "" || p == "direct" || p == "off" {
+			continue
+		}
+		proxies = append(proxies, p)
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("GOPROXY=%s does not name a module proxy", raw)
+	}
+	return proxies, nil
+}
+
+// installFromGoProxy 从 GOPROXY 下载 golang.org/toolchain 模块 zip，
+// 按校验和数据库中的 h1: 摘要校验后解压到安装目录。
+func (vm *VersionManager) installFromGoProxy(version string, opts InstallOptions) error {
+	if opts.Checksum != "" {
+		return fmt.Errorf("--checksum cannot be used with GVM_SOURCE=goproxy; toolchain modules are verified against the checksum database")
+	}
+	proxies, err := goProxies()
+	if err != nil {
+		return err
+	}
+
+	modVersion := toolchainModuleVersion(version, runtime.GOOS, runtime.GOARCH)
+	filename := fmt.Sprintf("toolchain@%s.zip", modVersion)
+	tempFile, cached := cachedArchivePath(filename)
+	if !cached {
+		defer os.Remove(tempFile)
+	}
+
+	var sourceURL string
+	var downloadErr error
+	for _, proxy := range proxies {
+		url := fmt.Sprintf("%s/%s/@v/%s.zip", proxy, ToolchainModule, modVersion)
+		fmt.Printf("Downloading %s@%s from %s...\n", ToolchainModule, modVersion, proxy)
+		_, downloadErr = utils.DownloadFileWithOptions(url, tempFile, utils.DownloadOptions{
+			Resume:        !opts.NoResume,
+			Proxy:         vm.settings.Proxy,
+			Downloader:    vm.settings.Downloader,
+			RedirectHosts: vm.settings.RedirectHosts,
+		})
+		if downloadErr == nil {
+			sourceURL = url
+			break
+		}
+	}
+	if downloadErr != nil {
+		if utils.IsNotFound(downloadErr) {
+			return newError(CodeVersionNotFound, "%s@%s is not available from GOPROXY: %w", ToolchainModule, modVersion, downloadErr)
+		}
+		return phaseError(ErrDownload, CodeDownloadFailed, "failed to download %s@%s: %w", ToolchainModule, modVersion, downloadErr)
+	}
+
+	// 校验：模块 zip 没有 SHA256 校验值，使用校验和数据库中的 h1: 摘要
+	actual, err := utils.ZipHash1(tempFile)
+	if err != nil {
+		_ = os.Remove(tempFile)
+		return phaseError(ErrExtract, CodeExtractFailed, "failed to read %s: %w", filename, err)
+	}
+	expected, err := vm.lookupModuleSum(proxies, ToolchainModule, modVersion)
+	switch {
+	case err == errSumDBOff:
+		fmt.Println("Warning: the checksum database is disabled (GOSUMDB=off, GONOSUMDB or GOPRIVATE), the toolchain module is not verified")
+	case err != nil:
+		return phaseError(ErrChecksum, CodeChecksumMismatch, "failed to look up the checksum of %s@%s: %w", ToolchainModule, modVersion, err)
+	case expected != actual:
+		quarantineMismatch(tempFile, QuarantineRecord{File: filename, URL: sourceURL, Expected: expected, Actual: actual})
+		return phaseError(ErrChecksum, CodeChecksumMismatch, "failed to verify %s: expected %s, got %s", filename, expected, actual)
+	}
+
+	if err := utils.EnsureDir(vm.installDir); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	installPath := filepath.Join(vm.installDir, version)
+	fmt.Printf("Extracting to %s...\n", installPath)
+	extractOpts := utils.ExtractOptions{StripPrefix: fmt.Sprintf("%s@%s/", ToolchainModule, modVersion)}
+	if opts.NoSrc {
+		extractOpts.Exclude = []string{"src"}
+	}
+	stagePath, err := vm.extractStaged(tempFile, filename, version, "", extractOpts)
+	if err != nil {
+		return phaseError(ErrExtract, CodeExtractFailed, "failed to extract %s: %w", filename, err)
+	}
+	return finishInstall(stagePath, installPath, version, opts)
+}
+
+// errSumDBOff 表示 GOSUMDB=off 或模块匹配 GONOSUMDB/GOPRIVATE，不进行校验
+var errSumDBOff = fmt.Errorf("checksum database disabled")
+
+// parseSumDBLookup 从校验和数据库 lookup 响应中取出 "<module> <version> h1:..." 行的摘要
+func parseSumDBLookup(body, module, modVersion string) string {
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == module && fields[1] == modVersion && strings.HasPrefix(fields[2], "h1:") {
+			return fields[2]
+		}
+	}
+	return ""
+}
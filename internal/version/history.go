@@ -0,0 +1,99 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// 版本切换的来源，记录在 ~/.gvm/history.log 中
+const (
+	SwitchManual  = "manual"  // gvm use <version>
+	SwitchDotfile = "dotfile" // gvm use --from-gomod 或 --from-file，Detail 为文件路径
+	SwitchAlias   = "alias"   // 通过 gvm link 或 gvm alias 创建的名称切换，Detail 为名称
+	SwitchInstall = "install" // gvm install 安装后自动激活
+	SwitchImport  = "import"  // gvm import 恢复清单中的当前版本
+)
+
+// HistoryEntry 是一次版本切换的记录
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	From   string    `json:"from"` // 切换前的版本，没有时为空
+	To     string    `json:"to"`
+	Source string    `json:"source"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// historyPath 返回切换记录文件路径（~/.gvm/history.log）
+func historyPath() (string, error) {
+	homeDir, err := utils.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".gvm", "history.log"), nil
+}
+
+// appendHistory 向 history.log 追加一行以制表符分隔的记录：时间、原版本、新版本、来源、说明
+func appendHistory(e HistoryEntry) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	from := e.From
+	if from == "" {
+		from = "-"
+	}
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339), from, e.To, e.Source, e.Detail)
+	return err
+}
+
+// ReadHistory 按时间顺序返回 history.log 中的切换记录；文件不存在时返回空列表，无法解析的行被跳过
+func ReadHistory() ([]HistoryEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []HistoryEntry{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []HistoryEntry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 5)
+		if len(fields) < 4 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			continue
+		}
+		e := HistoryEntry{Time: t, From: fields[1], To: fields[2], Source: fields[3]}
+		if e.From == "-" {
+			e.From = ""
+		}
+		if len(fields) == 5 {
+			e.Detail = fields[4]
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
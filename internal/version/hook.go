@@ -0,0 +1,58 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// runPostUseHook 在切换成功后通过 shell 执行配置的 post-use 钩子。
+// 钩子可以从环境变量 GVM_VERSION、GVM_PREVIOUS_VERSION 与 GOROOT 得知新旧版本，
+// 新版本的 bin 目录位于 PATH 最前，因此钩子中的 go 即为新版本。toStderr 为 true 时钩子的标准输出写到 stderr。
+func runPostUseHook(command, version, previous, goroot string, toStderr bool) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", command)
+	}
+	cmd.Env = hookEnv(os.Environ(), version, previous, goroot)
+	cmd.Stdout = os.Stdout
+	if toStderr {
+		cmd.Stdout = os.Stderr
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post-use hook %q failed: %w", command, err)
+	}
+	return nil
+}
+
+// hookEnv 基于 environ 构造钩子的环境变量
+func hookEnv(environ []string, version, previous, goroot string) []string {
+	binPath := filepath.Join(goroot, "bin")
+	env := make([]string, 0, len(environ)+4)
+	path := binPath
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		switch {
+		case strings.EqualFold(key, "PATH"):
+			if value != "" {
+				path = binPath + string(os.PathListSeparator) + value
+			}
+			continue
+		case key == "GOROOT", key == "GVM_VERSION", key == "GVM_PREVIOUS_VERSION":
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env,
+		"PATH="+path,
+		"GOROOT="+goroot,
+		"GVM_VERSION="+version,
+		"GVM_PREVIOUS_VERSION="+previous,
+	)
+}
@@ -0,0 +1,93 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// MoveInstallDir 将已安装的版本移动到 newDir，把 newDir 写入 config.json 的 install_dir，
+// 并让 go shim、命名链接与 ~/.gvm/go 指向新位置；返回被移动的版本。
+// 版本逐个重命名，任何一个失败（例如跨文件系统）时已移动的版本会被移回，配置保持不变。
+func (vm *VersionManager) MoveInstallDir(newDir string) ([]string, error) {
+	newDir, err := filepath.Abs(newDir)
+	if err != nil {
+		return nil, err
+	}
+	oldDir := vm.installDir
+	if newDir == filepath.Clean(oldDir) {
+		return nil, nil
+	}
+	if utils.PathWithin(newDir, oldDir) {
+		return nil, fmt.Errorf("%s is inside the current install directory %s", newDir, oldDir)
+	}
+	versions, err := vm.GetInstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.EnsureDir(newDir); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", newDir, err)
+	}
+
+	var moved []string
+	rollback := func() {
+		for _, v := range moved {
+			_ = os.Rename(filepath.Join(newDir, v), filepath.Join(oldDir, v))
+		}
+	}
+	for _, v := range versions {
+		dest := filepath.Join(newDir, v)
+		if _, err := os.Lstat(dest); err == nil {
+			rollback()
+			return nil, fmt.Errorf("%s already exists", dest)
+		}
+		lock, _, err := utils.AcquireLock(v, utils.LockTimeout)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		err = os.Rename(filepath.Join(oldDir, v), dest)
+		lock.Release()
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("failed to move %s: %w; move the versions in %s to %s yourself and run the command again", v, err, oldDir, newDir)
+		}
+		moved = append(moved, v)
+	}
+
+	if err := config.SetInstallDir(newDir); err != nil {
+		rollback()
+		return nil, fmt.Errorf("failed to update config: %w", err)
+	}
+	vm.installDir = newDir
+	if err := vm.relinkShims(); err != nil {
+		return moved, fmt.Errorf("moved the versions but failed to update the shims: %w", err)
+	}
+	return moved, nil
+}
+
+// relinkShims 让 go shim、命名链接与 ~/.gvm/go 指向安装目录中的对应版本
+func (vm *VersionManager) relinkShims() error {
+	current, err := config.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	if installed, _ := vm.IsVersionInstalled(current); current != "" && installed {
+		if err := utils.UpdateShims(filepath.Join(vm.installDir, current, "bin")); err != nil {
+			return err
+		}
+	}
+	links, err := config.GetLinks()
+	if err != nil {
+		return err
+	}
+	for name, v := range links {
+		if err := utils.WriteNamedShim(name, filepath.Join(vm.installDir, v, "bin")); err != nil {
+			return err
+		}
+	}
+	return vm.SyncStableRoot()
+}
@@ -0,0 +1,96 @@
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxReportedUses 限制错误信息中列出的外部使用数量
+const maxReportedUses = 5
+
+// ExternalUses 检查 version 是否在 gvm 的当前版本记录之外被使用，返回每处使用的说明：
+//   - 当前环境的 GOROOT 指向该版本（例如手动 export 或 gvm shell）
+//   - Linux 上，当前用户可见的进程正在运行该版本中的程序，或其环境中的 GOROOT 指向该版本
+//
+// 卸载这样的版本会让已打开的终端或正在进行的构建失效。
+func (vm *VersionManager) ExternalUses(version string) []string {
+	installPath, err := filepath.Abs(filepath.Join(vm.installDir, version))
+	if err != nil {
+		return nil
+	}
+
+	var uses []string
+	envMatched := false
+	if goroot := os.Getenv("GOROOT"); goroot != "" && withinDir(goroot, installPath) {
+		uses = append(uses, "GOROOT in the current environment is "+goroot)
+		envMatched = true
+	}
+	if runtime.GOOS == "linux" {
+		uses = append(uses, procUses(installPath, envMatched)...)
+	}
+	return uses
+}
+
+// procUses 扫描 /proc 中当前用户可读的进程。envMatched 为 true 时跳过父进程的 GOROOT，
+// 它与当前环境中已报告的 GOROOT 相同。
+func procUses(installPath string, envMatched bool) []string {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	self, parent := os.Getpid(), os.Getppid()
+	var uses []string
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		dir := filepath.Join("/proc", e.Name())
+		comm, _ := os.ReadFile(filepath.Join(dir, "comm"))
+		label := fmt.Sprintf("process %d (%s)", pid, strings.TrimSpace(string(comm)))
+
+		if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil && withinDir(exe, installPath) {
+			uses = append(uses, label+" runs "+exe)
+			continue
+		}
+		if pid == parent && envMatched {
+			continue
+		}
+		environ, err := os.ReadFile(filepath.Join(dir, "environ"))
+		if err != nil {
+			continue
+		}
+		for _, kv := range bytes.Split(environ, []byte{0}) {
+			if goroot, ok := strings.CutPrefix(string(kv), "GOROOT="); ok && goroot != "" && withinDir(goroot, installPath) {
+				uses = append(uses, label+" has GOROOT="+goroot)
+				break
+			}
+		}
+	}
+	return uses
+}
+
+// withinDir 判断 path（解析符号链接后）是否为 dir 或其子路径
+func withinDir(path, dir string) bool {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// summarizeUses 将外部使用列表合并为一段说明，超过 maxReportedUses 时只列出前几项
+func summarizeUses(uses []string) string {
+	if len(uses) <= maxReportedUses {
+		return strings.Join(uses, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(uses[:maxReportedUses], "; "), len(uses)-maxReportedUses)
+}
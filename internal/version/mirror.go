@@ -0,0 +1,101 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+)
+
+// MirrorProvider 根据安装包信息生成下载地址，不同镜像可以使用不同的 URL 布局。
+// 校验值始终来自 go.dev 的版本 JSON（或 --checksum），与下载所用的镜像无关。
+type MirrorProvider interface {
+	Name() string
+	DownloadURL(f GoFile) string
+}
+
+// dlProvider 是 go.dev 风格的镜像：<base>/dl/<filename>
+type dlProvider struct {
+	base string
+}
+
+func (p dlProvider) Name() string { return p.base }
+
+func (p dlProvider) DownloadURL(f GoFile) string {
+	return fmt.Sprintf("%s/dl/%s", p.base, f.Filename)
+}
+
+// templateProvider 按 config.json 中的 URL 模板生成下载地址，支持的占位符：
+//
+//	{version}    不带 go 前缀的版本号，例如 1.21.6
+//	{goversion}  完整版本号，例如 go1.21.6
+//	{os} {arch}  目标平台，例如 linux、amd64
+//	{ext}        安装包扩展名，例如 tar.gz、zip
+//	{filename}   go.dev 上的原始文件名
+type templateProvider struct {
+	name     string
+	template string
+}
+
+func (p templateProvider) Name() string { return p.name }
+
+func (p templateProvider) DownloadURL(f GoFile) string {
+	r := strings.NewReplacer(
+		"{version}", strings.TrimPrefix(f.Version, "go"),
+		"{goversion}", f.Version,
+		"{os}", f.OS,
+		"{arch}", f.Arch,
+		"{ext}", archiveExt(f.Filename),
+		"{filename}", f.Filename,
+	)
+	return r.Replace(p.template)
+}
+
+// archiveExt 返回安装包文件名的扩展名（tar.gz、zip 等）
+func archiveExt(filename string) string {
+	for _, ext := range []string{"tar.gz", "tar.bz2", "zip"} {
+		if strings.HasSuffix(filename, "."+ext) {
+			return ext
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+// ValidateMirrorTemplate 检查 URL 模板是否可用：必须是 http(s) 地址并包含版本占位符
+func ValidateMirrorTemplate(template string) error {
+	if !strings.HasPrefix(template, "https://") && !strings.HasPrefix(template, "http://") {
+		return fmt.Errorf("mirror template %q must be an http(s) URL", template)
+	}
+	if !strings.Contains(template, "{version}") && !strings.Contains(template, "{goversion}") && !strings.Contains(template, "{filename}") {
+		return fmt.Errorf("mirror template %q must contain {version}, {goversion} or {filename}", template)
+	}
+	return nil
+}
+
+// downloadProviders 返回按优先级排列的下载镜像：config.json 中配置的模板镜像在前，
+// 内置的 go.dev 风格镜像（中国镜像与 Settings.Mirror）作为默认与回退
+func (vm *VersionManager) downloadProviders() ([]MirrorProvider, error) {
+	var providers []MirrorProvider
+	mirrors, err := config.GetMirrors()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mirrors {
+		if err := ValidateMirrorTemplate(m.URLTemplate); err != nil {
+			return nil, fmt.Errorf("invalid mirror %q in config: %w", m.Name, err)
+		}
+		name := m.Name
+		if name == "" {
+			name = m.URLTemplate
+		}
+		providers = append(providers, templateProvider{name: name, template: m.URLTemplate})
+	}
+	for _, base := range vm.baseURLs() {
+		providers = append(providers, dlProvider{base})
+	}
+	return providers, nil
+}
@@ -0,0 +1,216 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// DefaultReleasesURL 是查询 gvm 最新发布版本的地址，可通过 GVM_UPDATE_URL 覆盖
+const DefaultReleasesURL = "https://api.github.com/repos/philokun/gvm/releases/latest"
+
+// ReleasesPage 是提示中给出的发布页面
+const ReleasesPage = "https://github.com/philokun/gvm/releases"
+
+const (
+	updateCheckInterval = 24 * time.Hour         // 两次检查之间的最短间隔
+	updateCheckTimeout  = 5 * time.Second        // 单次查询的超时时间
+	updateCheckGrace    = 500 * time.Millisecond // 命令结束后最多再等待查询完成的时间
+)
+
+// buildVersion 可在构建时设置：go build -ldflags "-X github.com/philokun/gvm/internal/version.buildVersion=v1.2.0"
+var buildVersion string
+
+// GVMVersion 返回 gvm 自身的版本。优先使用构建时设置的版本，其次是 go install <module>@<version>
+// 记录的模块版本；本地 go build 的二进制没有版本信息，返回空字符串。
+func GVMVersion() string {
+	if buildVersion != "" {
+		return buildVersion
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return ""
+}
+
+// updateState 是 ~/.gvm/update-check.json 的内容
+type updateState struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest,omitempty"`
+}
+
+// updateStatePath 返回更新检查记录的路径
+func updateStatePath() (string, error) {
+	homeDir, err := utils.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".gvm", "update-check.json"), nil
+}
+
+func loadUpdateState() updateState {
+	var state updateState
+	path, err := updateStatePath()
+	if err != nil {
+		return state
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func saveUpdateState(state updateState) error {
+	path, err := updateStatePath()
+	if err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// StartUpdateCheck 在开启 notify-updates 时于后台检查 gvm 是否有新版本，每天最多查询一次。
+// 查询与命令并行进行，不会推迟命令本身的执行与输出。返回的函数在命令结束后调用，返回应提示的新版本
+// （没有时为空字符串）；当天的查询仍在进行时最多再等待 updateCheckGrace，超时则使用上一次检查记录的版本。
+// 未开启、或当前 gvm 没有版本信息时不发起任何请求。
+func StartUpdateCheck() func() string {
+	none := func() string { return "" }
+	current := GVMVersion()
+	if current == "" {
+		return none
+	}
+	if enabled, err := config.GetNotifyUpdates(); err != nil || !enabled {
+		return none
+	}
+
+	state := loadUpdateState()
+	latest := state.Latest
+	var done chan string
+	if time.Since(state.CheckedAt) >= updateCheckInterval {
+		// 先记录检查时间：即使命令在查询完成前结束，一天内也不会再次查询
+		state.CheckedAt = time.Now()
+		_ = saveUpdateState(state)
+		settings := config.ResolveSettings()
+		done = make(chan string, 1)
+		go func(state updateState) {
+			found, err := fetchLatestRelease(settings)
+			if err != nil {
+				output.PrintVerbose(fmt.Sprintf("Update check failed: %v", err))
+				close(done)
+				return
+			}
+			state.Latest = found
+			_ = saveUpdateState(state)
+			done <- found
+		}(state)
+	}
+
+	return func() string {
+		if done != nil {
+			select {
+			case found, ok := <-done:
+				if ok {
+					latest = found
+				}
+			case <-time.After(updateCheckGrace):
+			}
+		}
+		if newerRelease(current, latest) {
+			return latest
+		}
+		return ""
+	}
+}
+
+// fetchLatestRelease 查询最新发布版本的标签
+func fetchLatestRelease(settings config.Settings) (string, error) {
+	url := os.Getenv("GVM_UPDATE_URL")
+	if url == "" {
+		url = DefaultReleasesURL
+	}
+	client, err := utils.NewHTTPClient(updateCheckTimeout, settings.Proxy, settings.RedirectHosts)
+	if err != nil {
+		return "", err
+	}
+	// 私有镜像的凭据不能发给 GitHub
+	req, err := utils.NewPublicRequest(url)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &utils.StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to parse release info: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("release info from %s has no tag_name", url)
+	}
+	return release.TagName, nil
+}
+
+// newerRelease 判断 latest 是否比 current 新。两者为 vMAJOR.MINOR.PATCH 形式，
+// 预发布或伪版本后缀只比较数字部分；无法解析时返回 false。
+func newerRelease(current, latest string) bool {
+	c, ok := releaseNumbers(current)
+	if !ok {
+		return false
+	}
+	l, ok := releaseNumbers(latest)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// releaseNumbers 解析 v1.2.3（可带 -rc1 等后缀）中的三个数字
+func releaseNumbers(v string) ([3]int, bool) {
+	var nums [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return nums, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nums, false
+		}
+		nums[i] = n
+	}
+	return nums, true
+}
@@ -0,0 +1,105 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// OfficialBaseURL 是校验值的权威来源。GVM_VERIFY_FROM_OFFICIAL 开启时，
+// 无论安装包从哪个镜像下载，期望的 SHA256 都从这里获取，镜像即使同时篡改安装包与校验值也无法通过校验。
+const OfficialBaseURL = config.DefaultMirror
+
+// useOfficialChecksums 将 files 中的 SHA256 替换为 go.dev 给出的值。
+// 严格模式下无法获取官方校验值时返回错误；warn 模式下只打印警告，保留镜像提供的值。
+func (vm *VersionManager) useOfficialChecksums(version string, files []GoFile) error {
+	official, err := vm.fetchOfficialChecksums(version, files)
+	if err != nil {
+		if vm.settings.VerifyOfficial == config.VerifyOfficialWarn {
+			fmt.Fprintf(os.Stderr, "Warning: could not get checksums from %s (%v); using the checksums from the mirror\n", OfficialBaseURL, err)
+			return nil
+		}
+		return phaseError(ErrDownload, CodeDownloadFailed,
+			"GVM_VERIFY_FROM_OFFICIAL is set but the checksums could not be fetched from %s (set GVM_VERIFY_FROM_OFFICIAL=warn to fall back to the mirror): %w", OfficialBaseURL, err)
+	}
+	for i := range files {
+		sum := official[files[i].Filename]
+		if files[i].SHA256 != "" && utils.MatchSHA256(files[i].SHA256, sum) != nil {
+			fmt.Fprintf(os.Stderr, "Warning: the mirror lists checksum %s for %s but %s lists %s; using %s\n",
+				files[i].SHA256, files[i].Filename, OfficialBaseURL, sum, OfficialBaseURL)
+		}
+		files[i].SHA256 = sum
+	}
+	output.PrintVerbose(fmt.Sprintf("Using checksums from %s", OfficialBaseURL))
+	return nil
+}
+
+// fetchOfficialChecksums 从 go.dev 的版本 JSON 获取 files 的校验值；
+// 版本不在 JSON 中（已归档的旧版本）时改为读取 go.dev 上的 .sha256 旁路文件
+func (vm *VersionManager) fetchOfficialChecksums(version string, files []GoFile) (map[string]string, error) {
+	versions, err := vm.fetchOfficialVersions()
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[string]string)
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+		for _, f := range v.Files {
+			if f.SHA256 != "" {
+				sums[f.Filename] = f.SHA256
+			}
+		}
+	}
+
+	for _, f := range files {
+		if sums[f.Filename] != "" {
+			continue
+		}
+		sum, err := vm.fetchSidecarChecksum(f.Filename, OfficialBaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s has no checksum for %s: %w", OfficialBaseURL, f.Filename, err)
+		}
+		sums[f.Filename] = sum
+	}
+	return sums, nil
+}
+
+// fetchOfficialVersions 直接从 go.dev 获取完整版本列表，不使用镜像与版本缓存
+func (vm *VersionManager) fetchOfficialVersions() ([]GoVersion, error) {
+	client, err := utils.NewHTTPClient(vm.settings.HTTPTimeout, vm.settings.Proxy, vm.settings.RedirectHosts)
+	if err != nil {
+		return nil, err
+	}
+	url := OfficialBaseURL + "/dl/?mode=json&include=all"
+	// 私有镜像的凭据不能发给 go.dev
+	req, err := utils.NewPublicRequest(url)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	utils.LogFinalURL(resp, url)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &utils.StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var versions []GoVersion
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return nil, fmt.Errorf("%s did not return a version list: %w", OfficialBaseURL, err)
+	}
+	return versions, nil
+}
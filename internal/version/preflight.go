@@ -0,0 +1,55 @@
+package version
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// CheckWritable 在安装或切换版本前确认 ~/.gvm 与安装目录可写：在其中创建并删除一个探测文件。
+// 只读文件系统、权限不足或磁盘已满时返回一条说明原因的错误，
+// 避免在操作中途才因为某次写入失败留下难以理解的底层错误。
+func (vm *VersionManager) CheckWritable() error {
+	gvmDir := filepath.Join(utils.HomeDir(), ".gvm")
+	for _, dir := range []string{gvmDir, vm.installDir} {
+		if err := probeWritable(dir); err != nil {
+			return newError(CodeNotWritable,
+				"cannot write to %s: %s; make it writable or point HOME to a writable location; GVM_HOME chooses another directory for the Go versions",
+				dir, errorReason(err))
+		}
+	}
+	return nil
+}
+
+// probeWritable 确保 dir 存在，并在其中写入、删除一个临时文件
+func probeWritable(dir string) error {
+	if err := utils.EnsureDir(dir); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".gvm-write-test-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	defer os.Remove(name)
+	if _, err := f.Write([]byte("gvm")); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// errorReason 返回路径错误中的底层原因，例如 "permission denied"、"read-only file system"
+func errorReason(err error) string {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+	return err.Error()
+}
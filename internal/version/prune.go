@@ -0,0 +1,100 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// PruneResult 记录单个版本的删除结果。
+type PruneResult struct {
+	Version string
+	Err     error
+}
+
+// PruneCandidates 返回可清理的版本：同一次版本系列中已有更新补丁版本的旧版本。
+// 当前使用的版本永远不会出现在结果中。
+func (vm *VersionManager) PruneCandidates() ([]string, error) {
+	installed, err := vm.GetInstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	current, _ := vm.GetCurrentVersion()
+
+	// 找出每个系列的最新版本
+	newest := make(map[string]string)
+	for _, v := range installed {
+		s := Series(v)
+		if n, ok := newest[s]; !ok || CompareVersions(v, n) > 0 {
+			newest[s] = v
+		}
+	}
+
+	candidates := []string{}
+	for _, v := range installed {
+		if v == current || newest[Series(v)] == v {
+			continue
+		}
+		candidates = append(candidates, v)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return CompareVersions(candidates[i], candidates[j]) < 0 })
+	return candidates, nil
+}
+
+// PruneVersions 使用最多 jobs 个并发删除给定版本的安装目录，结果顺序与输入一致。
+// 所有删除完成后，成功删除的版本在一次配置保存中移除，部分失败时配置仍与磁盘一致。
+// 当前使用的版本会被拒绝删除。
+func (vm *VersionManager) PruneVersions(versions []string, jobs int) ([]PruneResult, error) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	current, _ := vm.GetCurrentVersion()
+
+	results := make([]PruneResult, len(versions))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, v := range versions {
+		results[i].Version = v
+		if v == current {
+			results[i].Err = newError(CodeVersionInUse, "cannot uninstall currently active version %s", v)
+			continue
+		}
+		wg.Add(1)
+		go func(i int, v string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := os.RemoveAll(filepath.Join(vm.installDir, v)); err != nil {
+				results[i].Err = fmt.Errorf("failed to remove installation directory: %w", err)
+			}
+		}(i, v)
+	}
+	wg.Wait()
+
+	removed := []string{}
+	for _, r := range results {
+		if r.Err == nil {
+			removed = append(removed, r.Version)
+		}
+	}
+	if len(removed) > 0 {
+		links, _ := config.GetLinks()
+		if err := config.RemoveVersions(removed); err != nil {
+			return results, fmt.Errorf("failed to update config: %w", err)
+		}
+		// 清理指向已删除版本的命名 shim
+		for _, r := range removed {
+			for name, v := range links {
+				if v == r {
+					_ = utils.RemoveNamedShim(name)
+				}
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// QuarantineRecord 记录一个校验失败的安装包，写入隔离文件旁的 .json 文件
+type QuarantineRecord struct {
+	File     string    `json:"file"`            // 安装包文件名
+	URL      string    `json:"url"`             // 下载地址
+	Expected string    `json:"expected_sha256"` // 期望的摘要
+	Actual   string    `json:"actual_sha256"`   // 实际下载内容的摘要
+	Time     time.Time `json:"time"`
+}
+
+// quarantineDir 返回校验失败安装包的隔离目录（~/.gvm/cache/quarantine）
+func quarantineDir() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "quarantine"), nil
+}
+
+// QuarantineArchive 将校验失败的安装包 path 移入隔离目录，命名为
+// <文件名>.<时间>.mismatch，并在旁边写入记录来源与摘要的 .json 文件，返回隔离后的路径。
+// 保留镜像实际返回的内容，便于排查被篡改或配置错误的镜像。
+func QuarantineArchive(path string, rec QuarantineRecord) (string, error) {
+	dir, err := quarantineDir()
+	if err != nil {
+		return "", err
+	}
+	if err := utils.EnsureDir(dir); err != nil {
+		return "", err
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	dest := filepath.Join(dir, fmt.Sprintf("%s.%s.mismatch", rec.File, rec.Time.Format("20060102-150405")))
+	if err := utils.MoveFile(path, dest); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return dest, err
+	}
+	if err := os.WriteFile(dest+".json", append(data, '\n'), 0644); err != nil {
+		return dest, fmt.Errorf("failed to write quarantine record: %w", err)
+	}
+	return dest, nil
+}
+
+// quarantineMismatch 隔离校验失败的安装包并输出隔离路径；隔离失败时删除该文件
+func quarantineMismatch(path string, rec QuarantineRecord) {
+	dest, err := QuarantineArchive(path, rec)
+	if err != nil {
+		fmt.Printf("Warning: failed to quarantine %s: %v\n", rec.File, err)
+		_ = os.Remove(path)
+		return
+	}
+	fmt.Printf("Quarantined the mismatching download at %s\n", dest)
+}
@@ -0,0 +1,90 @@
+package version
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// ResolutionStep 是当前版本解析链中的一步
+type ResolutionStep struct {
+	Source string `json:"source"` // 例如 config、shim、PATH、GOROOT
+	Detail string `json:"detail"`
+}
+
+// Resolution 描述当前生效的 Go 版本是如何确定的
+type Resolution struct {
+	Steps   []ResolutionStep `json:"steps"`
+	Version string           `json:"version"` // 生效的版本；不是 gvm 安装的版本时为 system
+	GOROOT  string           `json:"goroot"`
+}
+
+// Resolve 依次检查 config.json 中选定的版本、go shim、PATH 中实际找到的 go 以及
+// GOROOT 环境变量，返回每一步的结论与最终生效的版本和 GOROOT。
+func (vm *VersionManager) Resolve() *Resolution {
+	r := &Resolution{}
+	add := func(source, detail string) {
+		r.Steps = append(r.Steps, ResolutionStep{Source: source, Detail: detail})
+	}
+
+	if current, err := config.GetCurrentVersion(); err != nil {
+		add("config", "cannot read config.json: "+err.Error())
+	} else if current == "" {
+		add("config", "no version selected with 'gvm use'")
+	} else {
+		add("config", "current_version is "+current)
+	}
+
+	if target, err := utils.ShimTarget("go"); err != nil {
+		add("shim", "go shim is missing")
+	} else {
+		add("shim", "go shim points to "+target)
+	}
+
+	goPath, err := exec.LookPath("go")
+	if err != nil {
+		add("PATH", "no go executable found on PATH")
+		return r
+	}
+	resolved, err := filepath.EvalSymlinks(goPath)
+	if err != nil {
+		resolved = goPath
+	}
+	if resolved != goPath {
+		add("PATH", "first go on PATH is "+goPath+" -> "+resolved)
+	} else {
+		add("PATH", "first go on PATH is "+goPath)
+	}
+
+	root := filepath.Dir(filepath.Dir(resolved))
+	r.GOROOT = root
+	if v, ok := vm.ManagedVersion(root); ok {
+		r.Version = v
+	} else {
+		r.Version = "system"
+		if v := readVersionFile(root); v != "" {
+			add("PATH", "go is not managed by gvm ("+v+")")
+		} else {
+			add("PATH", "go is not managed by gvm")
+		}
+	}
+
+	if goroot := strings.TrimSpace(os.Getenv("GOROOT")); goroot != "" {
+		add("GOROOT", "GOROOT environment variable overrides the toolchain root with "+goroot)
+		r.GOROOT = goroot
+	}
+	return r
+}
+
+// readVersionFile 返回 GOROOT 下 VERSION 文件的第一个非空行
+func readVersionFile(root string) string {
+	b, err := os.ReadFile(filepath.Join(root, "VERSION"))
+	if err != nil {
+		return ""
+	}
+	return versionLine(string(b))
+}
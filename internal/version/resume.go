@@ -0,0 +1,90 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// stagedMarker 是暂存目录中标记解压已完整结束的文件。它随暂存目录移动到安装位置，
+// 写入配置后才删除，因此版本目录中存在该文件说明安装在移动到位之后、写入配置之前中断
+const stagedMarker = ".gvm-staged"
+
+// stagedRecord 是 stagedMarker 的内容：解压来源的安装包及其摘要、解压选项
+type stagedRecord struct {
+	Archive string   `json:"archive"`
+	SHA256  string   `json:"sha256"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// markStaged 在解压完成后写入标记；没有摘要的暂存目录不会被复用
+func markStaged(stagePath, filename, sum string, opts utils.ExtractOptions) {
+	b, err := json.Marshal(stagedRecord{Archive: filename, SHA256: sum, Exclude: opts.Exclude})
+	if err != nil {
+		return
+	}
+	// 标记写入失败只是无法复用，不影响本次安装
+	_ = os.WriteFile(filepath.Join(stagePath, stagedMarker), b, 0644)
+}
+
+// resumableStaging 查找此前中断的安装留下的、已完整解压 filename（摘要为 expectedSHA）的暂存目录。
+// 标记缺失、安装包或解压选项不同的暂存目录来自中断的解压或其他安装方式，会被删除；没有可用的目录时返回空字符串
+func (vm *VersionManager) resumableStaging(version, filename, expectedSHA string, opts utils.ExtractOptions) string {
+	stale, err := filepath.Glob(filepath.Join(vm.installDir, "."+version+".staging-*"))
+	if err != nil {
+		return ""
+	}
+	found := ""
+	for _, dir := range stale {
+		if found == "" && expectedSHA != "" && stagedMatches(dir, filename, expectedSHA, opts) {
+			found = dir
+			continue
+		}
+		_ = os.RemoveAll(dir)
+	}
+	return found
+}
+
+// stagedMatches 判断暂存目录的标记是否与要安装的安装包及解压选项一致
+func stagedMatches(dir, filename, expectedSHA string, opts utils.ExtractOptions) bool {
+	b, err := os.ReadFile(filepath.Join(dir, stagedMarker))
+	if err != nil {
+		return false
+	}
+	var rec stagedRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return false
+	}
+	return rec.Archive == filename && utils.MatchSHA256(rec.SHA256, expectedSHA) == nil &&
+		(len(rec.Exclude) == 0 && len(opts.Exclude) == 0 || reflect.DeepEqual(rec.Exclude, opts.Exclude))
+}
+
+// unfinishedInstall 判断 installPath 是否来自在移动到位之后、写入配置之前中断的安装
+func unfinishedInstall(installPath string) bool {
+	return utils.FileExists(filepath.Join(installPath, stagedMarker))
+}
+
+// finishInterrupted 验证中断的安装已移动到位的版本目录并写入配置；验证失败时删除该目录，由调用方重新安装
+func (vm *VersionManager) finishInterrupted(version string, opts InstallOptions) (bool, error) {
+	installPath := filepath.Join(vm.installDir, version)
+	err := validateInstall(installPath, version, !opts.NoValidate)
+	if err == nil && !opts.NoValidate {
+		err = runGoVersion(installPath, opts.ValidateTimeout)
+	}
+	if err != nil {
+		fmt.Printf("Removing the incomplete install of %s (%v)\n", version, err)
+		if err := os.RemoveAll(installPath); err != nil {
+			return false, fmt.Errorf("failed to remove the incomplete install %s: %w", installPath, err)
+		}
+		return false, nil
+	}
+	if err := recordInstall(version, opts); err != nil {
+		return false, err
+	}
+	_ = os.Remove(filepath.Join(installPath, stagedMarker))
+	return true, nil
+}
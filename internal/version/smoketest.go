@@ -0,0 +1,97 @@
+package version
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// DefaultSmokeTestTimeout 是 test-install 编译示例程序的默认超时时间
+const DefaultSmokeTestTimeout = 2 * time.Minute
+
+const smokeTestSource = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello from gvm")
+}
+`
+
+// SmokeTest 使用指定版本在临时目录中编译一个最小的 hello 程序，
+// 用于发现仅检查文件存在无法发现的问题，例如解压不完整导致标准库缺失。
+// 编译失败时错误中包含编译器输出。
+func (vm *VersionManager) SmokeTest(version string, timeout time.Duration) error {
+	installed, err := vm.IsVersionInstalled(version)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	installPath := filepath.Join(vm.installDir, version)
+
+	// 只测试与当前平台一致的安装，其他平台的工具链无法在本机运行
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	if _, err := os.Stat(filepath.Join(installPath, "pkg", "tool", platform)); err != nil {
+		return fmt.Errorf("Go %s has no toolchain for %s; only installs for the current platform can be tested", version, platform)
+	}
+
+	// 以 --no-src 安装的版本缺少标准库源码，go build 必然失败
+	if _, err := os.Stat(filepath.Join(installPath, "src")); os.IsNotExist(err) {
+		return fmt.Errorf("Go %s was installed without src/ (--no-src); building code needs the standard library sources, reinstall it with --with-src", version)
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultSmokeTestTimeout
+	}
+
+	dir, err := os.MkdirTemp("", "gvm-test-install-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module hello\n"), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "hello.go"), []byte(smokeTestSource), 0644); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, goBinary(installPath), "build", "-o", filepath.Join(dir, "hello"+exeSuffix()), ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GOROOT="+installPath,
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+		"GO111MODULE=on",
+		"GOFLAGS=",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("'go build' did not finish within %s%s", timeout, capturedOutput(stdout.String(), stderr.String()))
+	}
+	if err != nil {
+		return fmt.Errorf("'go build' failed: %w%s", err, capturedOutput(stdout.String(), stderr.String()))
+	}
+	return nil
+}
+
+// exeSuffix 返回当前平台可执行文件的扩展名
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
@@ -0,0 +1,226 @@
+package version
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+	"golang.org/x/mod/sumdb"
+)
+
+// sumGolangOrgKey 是 sum.golang.org 的公钥，与 go 命令内置的相同
+const sumGolangOrgKey = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ec9ZJ6C7a/eBBA7"
+
+// parseGOSUMDB 解析 GOSUMDB（"<name>"、"<name>+<hash>+<key>" 或 "<key> <url>"），
+// 返回校验和数据库的名称、公钥与直接访问的地址。只写名称时只接受 go 命令内置公钥的数据库。
+func parseGOSUMDB(value string) (name, key, direct string, err error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", "", "", fmt.Errorf("invalid GOSUMDB %q", value)
+	}
+	key = fields[0]
+	if !strings.Contains(key, "+") {
+		switch key {
+		case defaultSumDB:
+			key = sumGolangOrgKey
+		case "sum.golang.google.cn":
+			// sum.golang.org 在中国的镜像，使用相同的公钥
+			key, direct = sumGolangOrgKey, "https://sum.golang.google.cn"
+		default:
+			return "", "", "", fmt.Errorf("GOSUMDB=%s has no public key; use the form <name>+<hash>+<key> [url]", value)
+		}
+	}
+	name, _, _ = strings.Cut(key, "+")
+	if len(fields) == 2 {
+		direct = strings.TrimRight(fields[1], "/")
+	}
+	if direct == "" {
+		direct = "https://" + name
+	}
+	return name, key, direct, nil
+}
+
+// sumDBOps 实现 sumdb.ClientOps：通过 GOPROXY 的 sumdb 代理接口或直接访问读取数据，
+// 最新的签名树保存在 ~/.gvm/sumdb 中，用于发现数据库分叉；瓦片缓存在 ~/.gvm/cache/sumdb 中
+type sumDBOps struct {
+	key     string
+	bases   []string // 按顺序尝试的地址：<proxy>/sumdb/<name> ...，最后是直接地址
+	client  *http.Client
+	lastErr string // SecurityError 报告的内容
+}
+
+func (o *sumDBOps) ReadRemote(path string) ([]byte, error) {
+	var lastErr error
+	for _, base := range o.bases {
+		url := base + path
+		req, err := utils.NewRequest(url)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := o.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = &utils.StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+			continue
+		}
+		utils.LogFinalURL(resp, url)
+		return body, nil
+	}
+	return nil, lastErr
+}
+
+// configPath 返回 sumdb 配置文件（<name>/latest）在 ~/.gvm/sumdb 中的路径
+func (o *sumDBOps) configPath(file string) (string, error) {
+	homeDir, err := utils.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".gvm", "sumdb", filepath.FromSlash(file)), nil
+}
+
+func (o *sumDBOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return []byte(o.key), nil
+	}
+	path, err := o.configPath(file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []byte{}, nil
+	}
+	return data, err
+}
+
+func (o *sumDBOps) WriteConfig(file string, old, new []byte) error {
+	path, err := o.configPath(file)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if !bytes.Equal(current, old) {
+		return sumdb.ErrWriteConflict
+	}
+	return writeFileAtomic(path, new)
+}
+
+func (o *sumDBOps) ReadCache(file string) ([]byte, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(dir, "sumdb", filepath.FromSlash(file)))
+}
+
+func (o *sumDBOps) WriteCache(file string, data []byte) {
+	dir, err := CacheDir()
+	if err != nil {
+		return
+	}
+	path := filepath.Join(dir, "sumdb", filepath.FromSlash(file))
+	_ = writeFileAtomic(path, data)
+}
+
+func (o *sumDBOps) Log(msg string) {
+	output.PrintVerbose(msg)
+}
+
+func (o *sumDBOps) SecurityError(msg string) {
+	o.lastErr = msg
+}
+
+// writeFileAtomic 先写入同目录的临时文件再重命名，避免并发的 gvm 读到写了一半的签名树
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := utils.EnsureDir(dir); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
+
+// lookupModuleSum 从校验和数据库查询模块 zip 的 h1: 摘要，并用 GOSUMDB 的公钥验证签名树与
+// 记录在树中的包含证明，代理即使同时篡改 zip 与摘要也无法通过。查询先经过 GOPROXY 的 sumdb 代理接口
+// （适用于只能访问代理的环境），失败时直接访问 GOSUMDB。
+// GOSUMDB=off 或模块匹配 GONOSUMDB（未设置时为 GOPRIVATE）时返回 errSumDBOff。
+func (vm *VersionManager) lookupModuleSum(proxies []string, module, modVersion string) (string, error) {
+	value := strings.TrimSpace(os.Getenv("GOSUMDB"))
+	if value == "off" {
+		return "", errSumDBOff
+	}
+	if value == "" {
+		value = defaultSumDB
+	}
+	name, key, direct, err := parseGOSUMDB(value)
+	if err != nil {
+		return "", err
+	}
+	client, err := utils.NewHTTPClient(vm.settings.HTTPTimeout, vm.settings.Proxy, vm.settings.RedirectHosts)
+	if err != nil {
+		return "", err
+	}
+	ops := &sumDBOps{key: key, client: client}
+	for _, proxy := range proxies {
+		ops.bases = append(ops.bases, proxy+"/sumdb/"+name)
+	}
+	ops.bases = append(ops.bases, direct)
+
+	db := sumdb.NewClient(ops)
+	nosumdb := os.Getenv("GONOSUMDB")
+	if nosumdb == "" {
+		nosumdb = os.Getenv("GOPRIVATE")
+	}
+	if nosumdb != "" {
+		db.SetGONOSUMDB(nosumdb)
+	}
+	lines, err := db.Lookup(module, modVersion)
+	switch {
+	case errors.Is(err, sumdb.ErrGONOSUMDB):
+		return "", errSumDBOff
+	case errors.Is(err, sumdb.ErrSecurity):
+		return "", fmt.Errorf("the checksum database %s failed verification: %s", name, ops.lastErr)
+	case err != nil:
+		return "", err
+	}
+	if sum := parseSumDBLookup(strings.Join(lines, "\n"), module, modVersion); sum != "" {
+		return sum, nil
+	}
+	return "", fmt.Errorf("the checksum database %s has no checksum for %s@%s", name, module, modVersion)
+}
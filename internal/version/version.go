@@ -3,6 +3,8 @@ package version
 // 包 version 提供了 Go 版本管理的核心功能，包括获取可用版本、安装、卸载和切换版本。
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,54 +13,175 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/output"
 	"github.com/philokun/gvm/internal/utils"
 )
 
 const (
+	// DefaultInstallDir 是相对主目录的默认安装目录；实际目录见 config.ResolveInstallDir
 	DefaultInstallDir = ".gvm/versions"
 )
 
-// getBaseURL 返回下载与版本 JSON 的基址，支持通过环境变量覆盖镜像
-func getBaseURL() string {
-	if v := os.Getenv("GVM_DL_MIRROR"); v != "" {
-		return strings.TrimRight(v, "/")
+// GoVersion 表示一个 Go 版本及其相关文件信息。
+type GoVersion struct {
+	Version string   `json:"version"` // 版本号，例如 "go1.20.5"
+	Stable  bool     `json:"stable"`  // 是否为稳定版本
+	Files   []GoFile `json:"files"`
+}
+
+// GoFile 表示某个 Go 版本提供的单个安装包文件。
+type GoFile struct {
+	Filename string `json:"filename"` // 文件名
+	OS       string `json:"os"`       // 操作系统
+	Arch     string `json:"arch"`     // 架构
+	Version  string `json:"version"`  // 版本号
+	SHA256   string `json:"sha256"`   // 文件的 SHA256 校验值
+	Size     int    `json:"size"`     // 文件大小
+	Kind     string `json:"kind"`     // 文件类型：archive、installer 或 source
+}
+
+// IsArchive 判断文件是否为 gvm 可以解压安装的压缩包（而不是 .msi/.pkg 安装程序或源码包）。
+// 没有 kind 字段的条目（旧缓存或按文件名构造的归档版本）按扩展名判断。
+func (f GoFile) IsArchive() bool {
+	if f.Kind != "" {
+		return f.Kind == "archive"
 	}
-	return "https://go.dev"
+	return strings.HasSuffix(f.Filename, ".tar.gz") || strings.HasSuffix(f.Filename, ".tar.bz2") || strings.HasSuffix(f.Filename, ".zip")
 }
 
-func getAltBaseURL() string {
-	return "https://golang.google.cn"
+// ArchiveFor 返回版本中适用于 goos/goarch 的压缩包，没有时返回 nil；有多个时按 SelectArchive 的规则选择
+func (v *GoVersion) ArchiveFor(goos, goarch string) *GoFile {
+	f, _ := v.SelectArchive(goos, goarch, nil)
+	return f
 }
 
-// GoVersion 表示一个 Go 版本及其相关文件信息。
-type GoVersion struct {
-	Version string `json:"version"` // 版本号，例如 "go1.20.5"
-	Stable  bool   `json:"stable"`  // 是否为稳定版本
-	Files   []struct {
-		Filename string `json:"filename"` // 文件名
-		OS       string `json:"os"`       // 操作系统
-		Arch     string `json:"arch"`     // 架构
-		Version  string `json:"version"`  // 版本号
-		SHA256   string `json:"sha256"`   // 文件的 SHA256 校验值
-		Size     int    `json:"size"`     // 文件大小
-	} `json:"files"`
+// SelectArchive 在适用于 goos/goarch 的文件中按固定规则选出要安装的压缩包，结果与 JSON 中的文件顺序无关：
+//  1. 只考虑压缩包（kind 为 archive，缺少 kind 时按扩展名判断），不使用 .msi/.pkg 等安装程序
+//  2. 文件名包含 prefer 中子串的优先，越靠前的子串越优先
+//  3. 该平台的默认格式优先：windows 为 .zip，其余为 .tar.gz
+//  4. 仍无法区分时取文件名字典序最小的
+//
+// 返回选中的文件以及按上述顺序排列的全部候选；没有候选时返回 nil。
+func (v *GoVersion) SelectArchive(goos, goarch string, prefer []string) (*GoFile, []GoFile) {
+	var candidates []GoFile
+	for _, f := range v.Files {
+		if f.IsArchive() && f.OS == goos && f.Arch == goarch {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	preferRank := func(f GoFile) int {
+		for i, p := range prefer {
+			if p != "" && strings.Contains(f.Filename, p) {
+				return i
+			}
+		}
+		return len(prefer)
+	}
+	defaultExt := ".tar.gz"
+	if goos == "windows" {
+		defaultExt = ".zip"
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if ra, rb := preferRank(a), preferRank(b); ra != rb {
+			return ra < rb
+		}
+		if da, db := strings.HasSuffix(a.Filename, defaultExt), strings.HasSuffix(b.Filename, defaultExt); da != db {
+			return da
+		}
+		return a.Filename < b.Filename
+	})
+	selected := candidates[0]
+	return &selected, candidates
+}
+
+// archiveFor 按 GVM_PREFER_FILES 选择当前平台的安装包；有多个候选时在 --verbose 下说明选择结果
+func (vm *VersionManager) archiveFor(v *GoVersion) *GoFile {
+	f, candidates := v.SelectArchive(runtime.GOOS, runtime.GOARCH, vm.settings.PreferFiles)
+	if len(candidates) > 1 {
+		names := make([]string, len(candidates))
+		for i, c := range candidates {
+			names[i] = c.Filename
+		}
+		output.PrintVerbose(fmt.Sprintf("%d packages match %s-%s (%s); using %s",
+			len(candidates), runtime.GOOS, runtime.GOARCH, strings.Join(names, ", "), f.Filename))
+	}
+	return f
+}
+
+// InstallReport 记录一次安装的下载来源，供调用方在结果中说明
+type InstallReport struct {
+	Mirror string // 提供安装包的镜像名称，使用缓存时为空
+	Cached bool   // 安装包来自下载缓存
+}
+
+// InstallOptions 控制安装行为的可选参数。
+type InstallOptions struct {
+	Checksum   string // 期望的 SHA256，非空时覆盖版本 JSON 中提供的值
+	Archived   bool   // 允许安装版本 JSON 中不存在的归档版本
+	NoResume   bool   // 禁用断点续传，强制重新下载
+	Parts      int    // 安装包的最大并行下载连接数，大于 1 时分段下载（不支持断点续传）
+	NoValidate bool   // 跳过 VERSION 一致性检查与 `go version` 执行（仍要求 go 二进制存在）
+	Force      bool   // 允许覆盖目标位置已存在的非 gvm Go 安装
+
+	ValidateTimeout time.Duration // 安装后 `go version` 验证的超时时间，0 表示 DefaultValidateTimeout
+	NoSrc           bool          // 不解压 src/（标准库与工具链源码），用于精简的 CI 镜像
+
+	// RetryMirrorOnChecksum 为 true 时，某个镜像提供的安装包校验失败会隔离该文件并改用下一个镜像，
+	// 而不是立即失败；每次切换都会输出镜像名与实际、期望的校验值
+	RetryMirrorOnChecksum bool
+	Report                *InstallReport // 非 nil 时填入本次安装的下载来源
+
+	listed <-chan error // 非 nil 时在解压前等待并行查询的版本列表，版本不在列表中时放弃安装
+}
+
+// waitListed 等待并行查询版本列表的结果（见 listed），未并行查询时返回 nil
+func (o InstallOptions) waitListed() error {
+	if o.listed == nil {
+		return nil
+	}
+	return <-o.listed
 }
 
 // VersionManager 是 Go 版本管理器，封装了所有版本管理相关的方法。
 type VersionManager struct {
-	installDir string // 安装目录
+	installDir string          // 安装目录
+	settings   config.Settings // 镜像、代理、超时等设置
 }
 
-// New 创建一个新的 VersionManager 实例。
+// New 创建一个新的 VersionManager 实例，安装目录依次取自 GVM_HOME、config.json 的 install_dir 与 ~/.gvm/versions。
 func New() *VersionManager {
-	homeDir, _ := os.UserHomeDir()
-	return &VersionManager{
-		installDir: filepath.Join(homeDir, DefaultInstallDir),
+	return NewWithSettings(config.ResolveInstallDir(), config.ResolveSettings())
+}
+
+// NewWithSettings 使用给定的安装目录与设置创建 VersionManager，不读取环境变量与 config.json；
+// 测试可以借此将镜像指向本地服务器
+func NewWithSettings(installDir string, settings config.Settings) *VersionManager {
+	if settings.HTTPTimeout <= 0 {
+		settings.HTTPTimeout = config.DefaultHTTPTimeout
+	}
+	utils.SetHeaderHosts(settings.HeaderHosts)
+	return &VersionManager{installDir: installDir, settings: settings}
+}
+
+// baseURLs 返回按优先级排列的 go.dev 风格镜像基址：AltMirror 在前，Mirror 在后，忽略空值与重复项
+func (vm *VersionManager) baseURLs() []string {
+	var bases []string
+	for _, base := range []string{vm.settings.AltMirror, vm.settings.Mirror} {
+		if base != "" && (len(bases) == 0 || bases[0] != base) {
+			bases = append(bases, base)
+		}
 	}
+	return bases
 }
 
 // GetInstallDir 返回安装目录路径。
@@ -66,21 +189,70 @@ func (vm *VersionManager) GetInstallDir() string {
 	return vm.installDir
 }
 
-// GetAvailableVersions 获取 Go 官方提供的可用版本列表。
+// GetAvailableVersions 获取 Go 官方提供的可用版本列表，优先使用未过期的本地缓存。
 func (vm *VersionManager) GetAvailableVersions() ([]GoVersion, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
+	versions, _, err := vm.LoadAvailableVersions(false)
+	return versions, err
+}
+
+// VersionsSource 描述版本列表来自缓存还是网络，以及获取时间
+type VersionsSource struct {
+	FromCache bool      `json:"from_cache"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Mirror    string    `json:"mirror,omitempty"` // 从网络获取时提供版本列表的镜像
+	Stale     bool      `json:"stale,omitempty"`  // 所有镜像的版本 JSON 都不可用，使用了过期的缓存
+}
+
+// LoadAvailableVersions 与 GetAvailableVersions 相同，但同时返回列表的来源。
+// refresh 为 true 时跳过缓存，从网络获取并重新写入缓存。
+//
+// 版本列表与安装包的来源互不依赖：某些地区的镜像会拦截 /dl/?mode=json 而仍允许下载文件，
+// 此时只要之前获取过版本列表，就使用（可能已过期的）缓存并给出警告，安装包仍按镜像顺序下载。
+func (vm *VersionManager) LoadAvailableVersions(refresh bool) ([]GoVersion, VersionsSource, error) {
+	if !refresh {
+		if versions, fetchedAt, ok := LoadVersionsCache(VersionsCacheTTL); ok {
+			return versions, VersionsSource{FromCache: true, FetchedAt: fetchedAt}, nil
+		}
+	}
+	versions, mirror, err := vm.fetchAvailableVersions()
+	if err != nil {
+		if cached, fetchedAt, ok := LoadVersionsCache(0); ok {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the version list cached %s ago\n", err, time.Since(fetchedAt).Round(time.Minute))
+			return cached, VersionsSource{FromCache: true, FetchedAt: fetchedAt, Stale: true}, nil
+		}
+		return nil, VersionsSource{}, err
+	}
+	// 缓存写入失败不影响本次结果
+	_ = SaveVersionsCache(versions)
+	return versions, VersionsSource{FetchedAt: time.Now(), Mirror: mirror}, nil
+}
+
+// fetchAvailableVersions 从镜像获取版本列表（带镜像回退与重试），同时返回提供列表的镜像
+func (vm *VersionManager) fetchAvailableVersions() ([]GoVersion, string, error) {
+	client, err := utils.NewHTTPClient(vm.settings.HTTPTimeout, vm.settings.Proxy, vm.settings.RedirectHosts)
+	if err != nil {
+		return nil, "", err
+	}
 	// 优先使用中国镜像以提高速度
-	bases := []string{getAltBaseURL(), getBaseURL()}
+	bases := vm.baseURLs()
 	var lastErr error
 	for _, base := range bases {
+		if lastErr != nil {
+			output.PrintVerbose(fmt.Sprintf("Version list unavailable (%v); trying %s", lastErr, base))
+		}
 		url := fmt.Sprintf("%s/dl/?mode=json&include=all", base)
 		for i := 0; i < 3; i++ {
-			resp, err := client.Get(url)
+			req, err := utils.NewRequest(url)
+			if err != nil {
+				return nil, "", err
+			}
+			resp, err := client.Do(req)
 			if err != nil {
 				lastErr = err
 				time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
 				continue
 			}
+			utils.LogFinalURL(resp, url)
 			if resp.StatusCode != http.StatusOK {
 				lastErr = fmt.Errorf("bad status: %s", resp.Status)
 				resp.Body.Close()
@@ -94,16 +266,16 @@ func (vm *VersionManager) GetAvailableVersions() ([]GoVersion, error) {
 				time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
 				continue
 			}
+			// 被拦截的接口可能返回 200 的 HTML 页面，按无法解析处理并换下一个镜像
 			var versions []GoVersion
 			if err := json.Unmarshal(body, &versions); err != nil {
-				lastErr = err
-				time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
-				continue
+				lastErr = fmt.Errorf("%s did not return a version list: %w", base, err)
+				break
 			}
-			return versions, nil
+			return versions, base, nil
 		}
 	}
-	return nil, fmt.Errorf("failed to fetch Go versions: %w", lastErr)
+	return nil, "", fmt.Errorf("failed to fetch Go versions: %w", lastErr)
 }
 
 // GetLatestStable 返回最新稳定版的版本号（如 go1.21.5）
@@ -149,30 +321,120 @@ func (vm *VersionManager) GetCurrentVersion() (string, error) {
 	}
 
 	goRoot := filepath.Dir(filepath.Dir(goPath))
-	if !strings.Contains(goRoot, vm.installDir) {
-		return "system", nil
+	if version, ok := vm.ManagedVersion(goRoot); ok {
+		return version, nil
 	}
+	return "system", nil
+}
 
-	version := filepath.Base(goRoot)
-	return version, nil
+// ManagedVersion 判断 goRoot 是否为 gvm 安装目录下的某个版本目录，是则返回版本名。
+// 比较前规范化路径，Windows 上不区分大小写与分隔符。
+func (vm *VersionManager) ManagedVersion(goRoot string) (string, bool) {
+	if goRoot == "" {
+		return "", false
+	}
+	// 父目录与安装目录互为对方的前缀，即两者是同一目录
+	parent := filepath.Dir(filepath.Clean(goRoot))
+	if !utils.PathWithin(parent, vm.installDir) || !utils.PathWithin(vm.installDir, parent) {
+		return "", false
+	}
+	return filepath.Base(goRoot), true
 }
 
 // InstallVersion 安装指定的 Go 版本。
 func (vm *VersionManager) InstallVersion(version string) error {
+	return vm.InstallVersionWithOptions(version, InstallOptions{})
+}
+
+// InstallVersionWithOptions 按给定选项安装指定的 Go 版本。
+func (vm *VersionManager) InstallVersionWithOptions(version string, opts InstallOptions) error {
+	// 同一版本的并发安装（如 CI 矩阵共享 HOME）通过 ~/.gvm/locks/<version>.lock 互斥
+	lock, waited, err := utils.AcquireLock(version, utils.LockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	// 防止覆盖非 gvm 管理的 Go 安装；--force 会删除目标目录，必须在持有锁时检查，
+	// 以免删除另一个进程正在安装的同一版本
+	if err := vm.checkForeignGo(version, opts.Force); err != nil {
+		return err
+	}
+
 	// 检查版本是否已安装
 	installed, err := vm.IsVersionInstalled(version)
 	if err != nil {
 		return err
 	}
 	if installed {
-		return fmt.Errorf("version %s is already installed", version)
+		if waited {
+			// 等待期间已由另一个进程安装完成
+			fmt.Printf("Go %s was installed by another gvm process\n", version)
+			return nil
+		}
+		// 上次安装在移动到位之后、写入配置之前中断：验证后补全，验证失败则删除后重新安装
+		if unfinishedInstall(filepath.Join(vm.installDir, version)) {
+			fmt.Printf("Finishing the interrupted install of %s...\n", version)
+			finished, err := vm.finishInterrupted(version, opts)
+			if err != nil || finished {
+				return err
+			}
+			installed = false
+		}
+	}
+	if installed {
+		return newError(CodeAlreadyInstalled, "version %s is already installed", version)
 	}
 
-	// 获取可用的版本信息
-	availableVersions, err := vm.GetAvailableVersions()
+	switch vm.settings.Source {
+	case config.SourceGoDev:
+	case config.SourceGoProxy:
+		return vm.installFromGoProxy(version, opts)
+	default:
+		return fmt.Errorf("unknown source %q: expected %s or %s", vm.settings.Source, config.SourceGoDev, config.SourceGoProxy)
+	}
+
+	// 指定了校验值时不必先获取版本列表：按规范文件名直接下载，省去获取版本 JSON 的一次往返。
+	// 没有 --archived 时版本仍必须在列表中，列表与安装包并行获取，解压前检查。
+	// 镜像上没有该文件（404）时再查询版本 JSON，以便给出准确的错误或使用列表中的安装包。
+	var direct *GoVersion
+	var directErr error
+	if opts.Checksum != "" {
+		direct, err = vm.archivedVersion(version, opts.Checksum)
+		if err != nil {
+			return err
+		}
+		directOpts := opts
+		if !opts.Archived {
+			listed := make(chan error, 1)
+			go func() { listed <- vm.requireListed(version) }()
+			directOpts.listed = listed
+		}
+		directErr = vm.installFrom(version, direct, directOpts)
+		if !utils.IsNotFound(directErr) {
+			return directErr
+		}
+		fmt.Printf("%s was not found on the mirrors; looking up %s in the versions list...\n", direct.Files[0].Filename, version)
+	}
+
+	targetVersion, err := vm.lookupVersion(version, opts)
 	if err != nil {
 		return err
 	}
+	if direct != nil && vm.sameArchive(targetVersion, direct) {
+		// 版本列表给出的是同一个安装包，再下载一次也是 404
+		return directErr
+	}
+	return vm.installFrom(version, targetVersion, opts)
+}
+
+// lookupVersion 在版本 JSON 中查找 version；--archived 时找不到则按规范文件名构造
+func (vm *VersionManager) lookupVersion(version string, opts InstallOptions) (*GoVersion, error) {
+	// 获取可用的版本信息
+	availableVersions, err := vm.GetAvailableVersions()
+	if err != nil && !opts.Archived {
+		return nil, err
+	}
 
 	// 找到对应的版本信息
 	var targetVersion *GoVersion
@@ -184,126 +446,356 @@ func (vm *VersionManager) InstallVersion(version string) error {
 	}
 
 	if targetVersion == nil {
-		return fmt.Errorf("version %s not found in available versions", version)
+		if !opts.Archived {
+			return nil, newError(CodeVersionNotFound, "version %s not found in available versions", version)
+		}
+		// 归档版本：JSON 中不存在时按规范文件名直接构造下载地址
+		return vm.archivedVersion(version, opts.Checksum)
 	}
+	return targetVersion, nil
+}
 
-	// 找到适合当前系统的安装包
-	platform := fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
-	var targetFile *struct {
-		Filename string `json:"filename"`
-		OS       string `json:"os"`
-		Arch     string `json:"arch"`
-		Version  string `json:"version"`
-		SHA256   string `json:"sha256"`
-		Size     int    `json:"size"`
-	}
-
-	for i := range targetVersion.Files {
-		if targetVersion.Files[i].OS == runtime.GOOS && targetVersion.Files[i].Arch == runtime.GOARCH {
-			targetFile = &targetVersion.Files[i]
-			break
+// requireListed 检查 version 是否在版本 JSON 中，不在时返回与 lookupVersion 相同的错误
+func (vm *VersionManager) requireListed(version string) error {
+	availableVersions, err := vm.GetAvailableVersions()
+	if err != nil {
+		return err
+	}
+	for _, v := range availableVersions {
+		if v.Version == version {
+			return nil
 		}
 	}
+	return newError(CodeVersionNotFound, "version %s not found in available versions; pass --archived to install an archived release", version)
+}
+
+// sameArchive 判断两个版本信息中当前平台的安装包文件名是否相同
+func (vm *VersionManager) sameArchive(a, b *GoVersion) bool {
+	fa, _ := a.SelectArchive(runtime.GOOS, runtime.GOARCH, vm.settings.PreferFiles)
+	fb, _ := b.SelectArchive(runtime.GOOS, runtime.GOARCH, vm.settings.PreferFiles)
+	return fa != nil && fb != nil && fa.Filename == fb.Filename
+}
+
+// installFrom 下载、校验并解压 targetVersion 中适合当前平台的安装包
+func (vm *VersionManager) installFrom(version string, targetVersion *GoVersion, opts InstallOptions) error {
+	// 找到适合当前系统的安装包
+	platform := fmt.Sprintf("%s-%s", runtime.GOOS, runtime.GOARCH)
+	targetFile := vm.archiveFor(targetVersion)
 
 	if targetFile == nil {
-		return fmt.Errorf("no suitable package found for %s", platform)
+		return newError(CodeUnsupportedPlatform, "no suitable package found for %s; %s provides: %s",
+			platform, version, strings.Join(supportedPlatforms(targetVersion), ", "))
 	}
 
-	// 下载并安装（优先使用中国镜像，带镜像回退与重试）
-	// 优先使用中国镜像以提高下载速度
-	bases := []string{getAltBaseURL(), getBaseURL()}
-	var downloadURL string
-	tempFile := filepath.Join(os.TempDir(), targetFile.Filename)
+	// 下载并安装（优先使用 config.json 中的模板镜像，其次中国镜像，带镜像回退与重试）
+	providers, err := vm.downloadProviders()
+	if err != nil {
+		return err
+	}
 	var downloaded bool
-	
-	// 显示文件大小信息
-	fileSizeMB := float64(targetFile.Size) / (1024 * 1024)
-	fmt.Printf("Downloading %s (%.2f MB)...\n", targetFile.Filename, fileSizeMB)
-	
-	for _, base := range bases {
-		downloadURL = fmt.Sprintf("%s/dl/%s", base, targetFile.Filename)
-		for i := 0; i < 3; i++ {
-			if i > 0 {
-				fmt.Printf("Retrying download from %s (attempt %d/3)...\n", base, i+1)
-			}
-			if err := utils.DownloadFileWithProgress(downloadURL, tempFile, int64(targetFile.Size)); err != nil {
-				if i < 2 {
-					time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
-					continue
-				}
-				// 最后一次尝试失败，尝试下一个镜像
-				break
-			}
+	var digest string    // 安装包的 SHA256，下载时边写边计算
+	var sourceURL string // 实际下载安装包的地址
+
+	// GVM_VERIFY_FROM_OFFICIAL 开启时校验值改为取自 go.dev，不信任镜像提供的值
+	if opts.Checksum == "" && vm.settings.VerifyOfficial != config.VerifyOfficialOff {
+		files := []GoFile{*targetFile}
+		if err := vm.useOfficialChecksums(version, files); err != nil {
+			return err
+		}
+		targetFile = &files[0]
+	}
+
+	// 校验值（--checksum 指定的值优先于 JSON 中的值）
+	expectedSHA := targetFile.SHA256
+	if opts.Checksum != "" {
+		expectedSHA = opts.Checksum
+	}
+
+	installPath := filepath.Join(vm.installDir, version)
+	extractOpts := utils.ExtractOptions{}
+	if opts.NoSrc {
+		extractOpts.Exclude = []string{"src"}
+	}
+
+	// 中断的安装已完整解压过同一个（校验通过的）安装包时，跳过下载与解压，直接验证并完成安装
+	if stagePath := vm.resumableStaging(version, targetFile.Filename, expectedSHA, extractOpts); stagePath != "" {
+		if err := opts.waitListed(); err != nil {
+			return err
+		}
+		fmt.Printf("Resuming the interrupted install: using the files already extracted to %s\n", stagePath)
+		if opts.Report != nil {
+			opts.Report.Cached = true
+		}
+		return finishInstall(stagePath, installPath, version, opts)
+	}
+
+	// 安装包下载到 ~/.gvm/cache/downloads 并保留以便重装；GVM_CACHE_MAX_SIZE=0 时使用临时目录
+	tempFile, cached := cachedArchivePath(targetFile.Filename)
+	if cached {
+		if sum, ok := verifyCachedArchive(tempFile, expectedSHA); ok {
+			fmt.Printf("Using cached %s\n", targetFile.Filename)
 			downloaded = true
-			break
+			digest = sum
 		}
-		if downloaded {
-			break
+		if !downloaded {
+			if err := evictDownloadCache(int64(targetFile.Size)); err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			}
 		}
+	} else {
+		defer os.Remove(tempFile)
 	}
-	if !downloaded {
-		return fmt.Errorf("failed to download %s from all mirrors", targetFile.Filename)
+
+	// 显示文件大小信息
+	if !downloaded && targetFile.Size > 0 {
+		fileSizeMB := float64(targetFile.Size) / (1024 * 1024)
+		fmt.Printf("Downloading %s (%.2f MB)...\n", targetFile.Filename, fileSizeMB)
+	} else if !downloaded {
+		fmt.Printf("Downloading %s...\n", targetFile.Filename)
 	}
-	defer os.Remove(tempFile)
-	installPath := filepath.Join(vm.installDir, version)
 
+	if !downloaded {
+		fo := fetchOptions{resume: !opts.NoResume, parts: opts.Parts}
+		if opts.RetryMirrorOnChecksum {
+			fo.retryChecksum = expectedSHA
+		}
+		sum, url, err := vm.downloadFromProviders(providers, *targetFile, tempFile, fo)
+		if err != nil {
+			return phaseError(ErrDownload, CodeDownloadFailed, "failed to download %s from all mirrors: %w", targetFile.Filename, err)
+		}
+		digest = sum
+		sourceURL = url
+		if opts.Report != nil {
+			opts.Report.Mirror = mirrorName(providers, url, *targetFile)
+		}
+	} else if opts.Report != nil {
+		opts.Report.Cached = true
+	}
 	// 确保安装目录存在
 	if err := utils.EnsureDir(vm.installDir); err != nil {
 		return fmt.Errorf("failed to create install directory: %w", err)
 	}
 
-	// 下载已完成（上方循环），继续校验与解压
-
-	// 校验文件
-	if targetFile.SHA256 != "" {
-		if err := utils.VerifySHA256(tempFile, targetFile.SHA256); err != nil {
-			return fmt.Errorf("failed to verify sha256: %w", err)
+	// 校验文件：摘要已在下载时计算，这里只需比较
+	if expectedSHA != "" {
+		if err := utils.MatchSHA256(digest, expectedSHA); err != nil {
+			// 损坏的安装包不能留在缓存中；移入隔离目录而不是删除，便于排查镜像问题
+			quarantineMismatch(tempFile, QuarantineRecord{
+				File:     targetFile.Filename,
+				URL:      sourceURL,
+				Expected: expectedSHA,
+				Actual:   digest,
+			})
+			return phaseError(ErrChecksum, CodeChecksumMismatch, "failed to verify sha256: %w", err)
 		}
 	}
 
-	// 解压文件（根据扩展名）
+	if err := opts.waitListed(); err != nil {
+		return err
+	}
+
+	// 解压文件（根据文件内容识别格式，回退到扩展名）
 	fmt.Printf("Extracting to %s...\n", installPath)
-	if strings.HasSuffix(strings.ToLower(targetFile.Filename), ".tar.gz") {
-		if err := utils.ExtractTarGz(tempFile, installPath); err != nil {
-			return fmt.Errorf("failed to extract tar.gz: %w", err)
+	stagePath, err := vm.extractStaged(tempFile, targetFile.Filename, version, digest, extractOpts)
+	if err != nil {
+		return phaseError(ErrExtract, CodeExtractFailed, "failed to extract %s: %w", targetFile.Filename, err)
+	}
+	return finishInstall(stagePath, installPath, version, opts)
+}
+
+// verifyCachedArchive 检查下载缓存中的安装包是否与 expectedSHA 一致，返回其摘要。
+// 缓存中已有的文件只能重新读取计算摘要；不一致的文件会被删除，没有校验值时不使用缓存。
+func verifyCachedArchive(path, expectedSHA string) (string, bool) {
+	if expectedSHA == "" || !utils.FileExists(path) {
+		return "", false
+	}
+	sum, err := utils.ComputeSHA256(path)
+	if err != nil || utils.MatchSHA256(sum, expectedSHA) != nil {
+		_ = os.Remove(path)
+		return "", false
+	}
+	touchCachedArchive(path)
+	return sum, true
+}
+
+// extractStaged 将安装包解压到安装目录中的临时目录 .<version>.staging-<pid>，返回该目录。
+// 暂存目录与最终位置位于同一文件系统，验证通过后可以原子地重命名到位；
+// 解压中断不会留下看起来已安装的版本目录。失败时删除暂存目录。
+// 解压完成后在暂存目录中写入标记，记录安装包校验通过的摘要 sum（可以为空），
+// 进程在完成安装前被终止时下次安装可以据此复用暂存目录或补全安装。
+func (vm *VersionManager) extractStaged(archivePath, filename, version, sum string, opts utils.ExtractOptions) (string, error) {
+	// 同一版本的安装由锁互斥，此前遗留的暂存目录都来自中断的安装
+	if stale, err := filepath.Glob(filepath.Join(vm.installDir, "."+version+".staging-*")); err == nil {
+		for _, dir := range stale {
+			_ = os.RemoveAll(dir)
 		}
-	} else if strings.HasSuffix(strings.ToLower(targetFile.Filename), ".zip") {
-		if err := utils.ExtractZip(tempFile, installPath); err != nil {
-			return fmt.Errorf("failed to extract zip: %w", err)
+	}
+	stagePath := filepath.Join(vm.installDir, fmt.Sprintf(".%s.staging-%d", version, os.Getpid()))
+	if err := utils.EnsureDir(stagePath); err != nil {
+		return "", err
+	}
+	if err := utils.ExtractArchiveWithOptions(archivePath, filename, stagePath, opts); err != nil {
+		_ = os.RemoveAll(stagePath)
+		// 损坏的安装包留在缓存中会让重试继续失败（或被断点续传接着使用）
+		if utils.IsCorruptArchive(err) {
+			_ = os.Remove(archivePath)
 		}
-	} else {
-		return fmt.Errorf("unsupported package format: %s", targetFile.Filename)
+		return "", err
 	}
+	markStaged(stagePath, filename, sum, opts)
+	return stagePath, nil
+}
 
-	// 安装后验证：读取 VERSION 文件并检查二进制存在
-	verFile := filepath.Join(installPath, "VERSION")
-	b, err := os.ReadFile(verFile)
-	if err != nil {
-		_ = os.RemoveAll(installPath)
-		return fmt.Errorf("validation failed: missing VERSION: %w", err)
+// finishInstall 验证暂存目录中解压的结果，通过后将其移动到 installPath 并记录到配置中；
+// 验证失败时删除暂存目录
+func finishInstall(stagePath, installPath, version string, opts InstallOptions) error {
+	// 将解压结果刷到磁盘，避免网络文件系统上的同步延迟
+	_ = utils.SyncDir(stagePath)
+	_ = utils.SyncDir(filepath.Join(stagePath, "bin"))
+
+	// 安装后验证：读取 VERSION 文件并检查二进制存在（在慢速文件系统上短暂重试）
+	// --no-validate 时只检查二进制存在，不要求 VERSION 与版本号一致
+	if err := validateInstallWithRetry(stagePath, version, !opts.NoValidate); err != nil {
+		_ = os.RemoveAll(stagePath)
+		return phaseError(ErrValidate, CodeValidateFailed, "%w", err)
 	}
-	installedVer := strings.TrimSpace(string(b))
-	if installedVer != version {
-		_ = os.RemoveAll(installPath)
-		return fmt.Errorf("validation failed: version mismatch: expected %s got %s", version, installedVer)
+	// 执行一次 `go version`，尽早发现无法在本机运行的工具链（错误架构、缺少 libc 等）
+	if !opts.NoValidate {
+		if err := runGoVersion(stagePath, opts.ValidateTimeout); err != nil {
+			_ = os.RemoveAll(stagePath)
+			return phaseError(ErrValidate, CodeValidateFailed, "%w", err)
+		}
 	}
-	goBin := filepath.Join(installPath, "bin", "go")
-	if runtime.GOOS == "windows" {
-		goBin = filepath.Join(installPath, "bin", "go.exe")
+
+	if err := utils.RenameDir(stagePath, installPath); err != nil {
+		_ = os.RemoveAll(stagePath)
+		return phaseError(ErrExtract, CodeExtractFailed, "failed to move the extracted files into %s: %w", installPath, err)
 	}
-	if _, err := os.Stat(goBin); err != nil {
-		_ = os.RemoveAll(installPath)
-		return fmt.Errorf("validation failed: go binary missing: %w", err)
+	_ = utils.SyncDir(filepath.Dir(installPath))
+	if err := recordInstall(version, opts); err != nil {
+		return err
 	}
+	_ = os.Remove(filepath.Join(installPath, stagedMarker))
+	return nil
+}
 
-	// 更新配置
+// recordInstall 将已安装的版本写入配置
+func recordInstall(version string, opts InstallOptions) error {
 	if err := config.AddVersion(version); err != nil {
 		return fmt.Errorf("failed to update config: %w", err)
 	}
+	if opts.NoValidate {
+		if err := config.SetVersionUnvalidated(version, true); err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
+		}
+	}
 
 	return nil
 }
 
+// fetchOptions 控制 downloadFromProviders 的下载方式
+type fetchOptions struct {
+	resume bool // 断点续传
+	parts  int  // 最大并行连接数，见 utils.DownloadOptions.Parts
+	// retryChecksum 非空时，镜像下载的内容与该校验值不一致则隔离该文件并换下一个镜像；
+	// 最后一个镜像的结果原样返回，由调用方校验并报告错误
+	retryChecksum string
+}
+
+// downloadFromProviders 依次从各镜像下载安装包到 dest，每个镜像最多尝试 3 次，
+// 返回安装包的 SHA256 与成功下载的地址
+func (vm *VersionManager) downloadFromProviders(providers []MirrorProvider, f GoFile, dest string, fo fetchOptions) (string, string, error) {
+	var downloadErr error
+providers:
+	for n, provider := range providers {
+		downloadURL := provider.DownloadURL(f)
+		for i := 0; i < 3; i++ {
+			if i > 0 {
+				fmt.Printf("Retrying download from %s (attempt %d/3)...\n", provider.Name(), i+1)
+			}
+			dlOpts := utils.DownloadOptions{
+				ExpectedSize: int64(f.Size),
+				Resume:       fo.resume,
+				Proxy:        vm.settings.Proxy,
+				Downloader:   vm.settings.Downloader,
+				Parts:        fo.parts,
+
+				RedirectHosts: vm.settings.RedirectHosts,
+			}
+			sum, err := utils.DownloadFileWithOptions(downloadURL, dest, dlOpts)
+			if err != nil {
+				downloadErr = err
+				if i < 2 {
+					time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
+					continue
+				}
+				// 最后一次尝试失败，尝试下一个镜像
+				break
+			}
+			if fo.retryChecksum != "" && n < len(providers)-1 && utils.MatchSHA256(sum, fo.retryChecksum) != nil {
+				output.PrintWarning(fmt.Sprintf("mirror %s served bytes with sha %s, expected %s; trying next mirror",
+					provider.Name(), sum, fo.retryChecksum))
+				quarantineMismatch(dest, QuarantineRecord{File: f.Filename, URL: downloadURL, Expected: fo.retryChecksum, Actual: sum})
+				downloadErr = fmt.Errorf("%s served a file that does not match its checksum", provider.Name())
+				continue providers
+			}
+			return sum, downloadURL, nil
+		}
+	}
+	return "", "", downloadErr
+}
+
+// mirrorName 返回下载地址 url 所属镜像的名称，找不到时返回 url 本身
+func mirrorName(providers []MirrorProvider, url string, f GoFile) string {
+	for _, p := range providers {
+		if p.DownloadURL(f) == url {
+			return p.Name()
+		}
+	}
+	return url
+}
+
+// cachedArchivePath 返回安装包的下载路径；下载缓存可用时位于 ~/.gvm/cache/downloads，
+// 否则位于临时目录（cached 为 false，调用方负责删除）
+func cachedArchivePath(filename string) (path string, cached bool) {
+	if CacheMaxSize() > 0 {
+		if dir, err := downloadCacheDir(); err == nil && utils.EnsureDir(dir) == nil {
+			return filepath.Join(dir, filename), true
+		}
+	}
+	return filepath.Join(os.TempDir(), filename), false
+}
+
+// checkForeignGo 检查安装目标是否已包含非 gvm 安装的 Go（存在 VERSION 文件但配置中没有记录）。
+// force 为 true 时移除目标目录中的非 gvm 安装后继续。
+func (vm *VersionManager) checkForeignGo(version string, force bool) error {
+	// 安装目录本身就是一个 Go 根目录（例如误将安装目录指向 /usr/local/go）
+	if utils.FileExists(filepath.Join(vm.installDir, "VERSION")) && !force {
+		return newError(CodeForeignInstall,
+			"install directory %s already contains a Go installation not managed by gvm; use --force to install into it anyway", vm.installDir)
+	}
+
+	installPath := filepath.Join(vm.installDir, version)
+	if !utils.FileExists(filepath.Join(installPath, "VERSION")) {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if _, managed := cfg.Versions[version]; managed || unfinishedInstall(installPath) {
+		return nil
+	}
+	if !force {
+		return newError(CodeForeignInstall,
+			"%s already contains a Go installation not managed by gvm; use --force to replace it", installPath)
+	}
+	if err := os.RemoveAll(installPath); err != nil {
+		return fmt.Errorf("failed to remove existing installation at %s: %w", installPath, err)
+	}
+	return nil
+}
+
 // IsVersionInstalled 检查指定版本是否已安装。
 func (vm *VersionManager) IsVersionInstalled(version string) (bool, error) {
 	installPath := filepath.Join(vm.installDir, version)
@@ -317,29 +809,114 @@ func (vm *VersionManager) IsVersionInstalled(version string) (bool, error) {
 	return true, nil
 }
 
-// UseVersion 切换当前使用的 Go 版本。
+// GetBinPath 返回指定版本的 bin 目录绝对路径；"system" 返回系统 go 所在目录。
+func (vm *VersionManager) GetBinPath(version string) (string, error) {
+	if version == "system" {
+		goPath, err := exec.LookPath("go")
+		if err != nil {
+			return "", fmt.Errorf("go command not found in PATH")
+		}
+		return filepath.Dir(goPath), nil
+	}
+	installed, err := vm.IsVersionInstalled(version)
+	if err != nil {
+		return "", err
+	}
+	if !installed {
+		return "", newError(CodeVersionNotInstalled, "version %s is not installed", version)
+	}
+	installPath := filepath.Join(vm.installDir, version)
+	if _, err := os.Stat(goBinary(installPath)); err != nil {
+		return "", fmt.Errorf("go binary missing for %s: %w", version, err)
+	}
+	return filepath.Abs(filepath.Join(installPath, "bin"))
+}
+
+// GoBinaryPath 返回指定版本 go 可执行文件的绝对路径，并确认其存在；"system" 返回 PATH 中的 go。
+func (vm *VersionManager) GoBinaryPath(version string) (string, error) {
+	binPath, err := vm.GetBinPath(version)
+	if err != nil {
+		return "", err
+	}
+	if version == "system" {
+		return exec.LookPath("go")
+	}
+	return goBinary(filepath.Dir(binPath)), nil
+}
+
+// UseOptions 是 UseVersionWithOptions 的可选参数
+type UseOptions struct {
+	Source string // 切换来源，为空时按 SwitchManual 记录
+	Detail string // 来源的补充说明，例如别名或 go.mod 路径
+	NoHook bool   // 不执行配置的 post-use 钩子
+	// HookToStderr 将钩子的标准输出写到 stderr，供 stdout 只输出机器可读结果的调用方使用
+	HookToStderr bool
+}
+
+// UseVersion 切换当前使用的 Go 版本，切换记录的来源为 manual
 func (vm *VersionManager) UseVersion(version string) error {
+	return vm.UseVersionWithOptions(version, UseOptions{})
+}
+
+// UseVersionWithOptions 切换当前使用的 Go 版本。依次更新配置、shim、stable-root 与 shell 配置，
+// 其中任一步失败时恢复切换前的当前版本、go shim 与 ~/.gvm/go，避免留下只切换了一半的状态。
+// 成功后执行配置的 post-use 钩子，并向 ~/.gvm/history.log 追加一条带来源的切换记录。
+func (vm *VersionManager) UseVersionWithOptions(version string, opts UseOptions) error {
 	installed, err := vm.IsVersionInstalled(version)
 	if err != nil {
 		return err
 	}
 	if !installed {
-		return fmt.Errorf("version %s is not installed", version)
+		return newError(CodeVersionNotInstalled, "version %s is not installed", version)
 	}
 
 	// 目标二进制路径
 	goBinPath := filepath.Join(vm.installDir, version, "bin")
 
+	prev := captureUseState()
+
 	// 更新配置文件
 	if err := config.SetCurrentVersion(version); err != nil {
 		return fmt.Errorf("failed to update config: %w", err)
 	}
 
+	if err := vm.activate(version, goBinPath); err != nil {
+		return prev.rollback(err)
+	}
+
+	// post-use 钩子失败默认只警告；开启 post-use-hook-strict 时视为切换失败并回滚
+	if hook, strict, _ := config.GetPostUseHook(); hook != "" && !opts.NoHook {
+		if err := runPostUseHook(hook, version, prev.version, filepath.Join(vm.installDir, version), opts.HookToStderr); err != nil {
+			if strict {
+				return prev.rollback(err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		}
+	}
+
+	// 切换记录只用于排查，写入失败不影响切换结果
+	source := opts.Source
+	if source == "" {
+		source = SwitchManual
+	}
+	_ = appendHistory(HistoryEntry{Time: time.Now(), From: prev.version, To: version, Source: source, Detail: opts.Detail})
+	return nil
+}
+
+// activate 完成 UseVersion 中配置之外的步骤：shim、stable-root 与 PATH
+func (vm *VersionManager) activate(version, goBinPath string) error {
 	// 更新 shims 指向选定版本
 	if err := utils.UpdateShims(goBinPath); err != nil {
 		return fmt.Errorf("failed to update shims: %w", err)
 	}
 
+	// 开启 stable-root 时同步 ~/.gvm/go 符号链接
+	if enabled, _ := config.GetStableRoot(); enabled {
+		if err := utils.UpdateStableRoot(filepath.Join(vm.installDir, version)); err != nil {
+			return err
+		}
+	}
+
 	// 确保 PATH 包含 shims 目录（一次性）
 	shimsDir, err := utils.GetShimsDir()
 	if err != nil {
@@ -358,20 +935,129 @@ func (vm *VersionManager) UseVersion(version string) error {
 	return nil
 }
 
+// useState 记录切换前的当前版本、go shim 目标与 ~/.gvm/go 目标，用于失败时回滚
+type useState struct {
+	version    string
+	shimTarget string // 为空表示切换前没有 go shim
+	stableRoot string // 为空表示切换前没有 ~/.gvm/go
+}
+
+func captureUseState() useState {
+	var s useState
+	s.version, _ = config.GetCurrentVersion()
+	s.shimTarget, _ = utils.ShimTarget("go")
+	if linkPath, err := utils.GetStableRootPath(); err == nil {
+		s.stableRoot, _ = os.Readlink(linkPath)
+	}
+	return s
+}
+
+// rollback 恢复切换前的状态，返回包含原始错误与回滚结果的错误
+func (s useState) rollback(cause error) error {
+	var errs []string
+	if err := config.SetCurrentVersion(s.version); err != nil {
+		errs = append(errs, "config: "+err.Error())
+	}
+	if s.shimTarget != "" {
+		if err := utils.UpdateShims(filepath.Dir(s.shimTarget)); err != nil {
+			errs = append(errs, "shim: "+err.Error())
+		}
+	} else if err := utils.RemoveNamedShim("go"); err != nil {
+		errs = append(errs, "shim: "+err.Error())
+	}
+	if s.stableRoot != "" {
+		if err := utils.UpdateStableRoot(s.stableRoot); err != nil {
+			errs = append(errs, "stable root: "+err.Error())
+		}
+	} else if enabled, _ := config.GetStableRoot(); enabled {
+		if err := utils.RemoveStableRoot(); err != nil {
+			errs = append(errs, "stable root: "+err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w (rolling back also failed: %s)", cause, strings.Join(errs, "; "))
+	}
+	previous := s.version
+	if previous == "" {
+		previous = "none"
+	}
+	return fmt.Errorf("%w (previous version %s restored)", cause, previous)
+}
+
+// IsActive 判断 version 是否已是当前版本，且 go shim（以及开启时的 ~/.gvm/go）已指向它，
+// 此时 UseVersion 不需要重写任何文件。
+func (vm *VersionManager) IsActive(version string) bool {
+	current, err := config.GetCurrentVersion()
+	if err != nil || current != version {
+		return false
+	}
+	target, err := utils.ShimTarget("go")
+	if err != nil || target != goBinary(filepath.Join(vm.installDir, version)) {
+		return false
+	}
+	if enabled, _ := config.GetStableRoot(); enabled {
+		linkPath, err := utils.GetStableRootPath()
+		if err != nil {
+			return false
+		}
+		if dest, err := os.Readlink(linkPath); err != nil || dest != filepath.Join(vm.installDir, version) {
+			return false
+		}
+	}
+	return true
+}
+
+// SyncStableRoot 根据配置创建或删除 ~/.gvm/go 符号链接；开启时指向当前版本目录。
+func (vm *VersionManager) SyncStableRoot() error {
+	enabled, err := config.GetStableRoot()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return utils.RemoveStableRoot()
+	}
+	current, err := config.GetCurrentVersion()
+	if err != nil {
+		return err
+	}
+	if installed, _ := vm.IsVersionInstalled(current); current == "" || !installed {
+		// 没有激活版本时等到下一次 use 再创建
+		return nil
+	}
+	return utils.UpdateStableRoot(filepath.Join(vm.installDir, current))
+}
+
+// UninstallOptions 控制 UninstallVersionWithOptions 的行为
+type UninstallOptions struct {
+	Force      bool // 一并删除指向该版本的命名链接与别名，并忽略 gvm 之外的使用（见 ExternalUses）
+	KeepConfig bool // 保留配置中的版本记录并标记为已删除，便于之后重新安装
+}
+
 // UninstallVersion 卸载指定的 Go 版本。
 func (vm *VersionManager) UninstallVersion(version string) error {
+	return vm.UninstallVersionWithOptions(version, UninstallOptions{})
+}
+
+// UninstallVersionWithOptions 按给定选项卸载指定的 Go 版本。仍有命名链接或别名指向该版本，
+// 或该版本在 gvm 之外被使用（GOROOT 指向它、有进程正在运行其中的程序）时拒绝卸载，除非指定 Force。
+func (vm *VersionManager) UninstallVersionWithOptions(version string, opts UninstallOptions) error {
 	installed, err := vm.IsVersionInstalled(version)
 	if err != nil {
 		return err
 	}
 	if !installed {
-		return fmt.Errorf("version %s is not installed", version)
+		return newError(CodeVersionNotInstalled, "version %s is not installed", version)
 	}
 
-	// 检查是否是当前使用的版本
-	current, _ := vm.GetCurrentVersion()
-	if current == version {
-		return fmt.Errorf("cannot uninstall currently active version %s", version)
+	uses := vm.ExternalUses(version)
+	links := vm.LinksTo(version)
+	aliases := vm.AliasesTo(version)
+	if err := vm.uninstallBlocker(version, uses, links, aliases, opts); err != nil {
+		return err
+	}
+	if len(uses) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: removing %s although it appears to be in use: %s\n", version, summarizeUses(uses))
 	}
 
 	installPath := filepath.Join(vm.installDir, version)
@@ -380,9 +1066,503 @@ func (vm *VersionManager) UninstallVersion(version string) error {
 	}
 
 	// 更新配置
-	if err := config.RemoveVersion(version); err != nil {
+	if opts.KeepConfig {
+		err = config.MarkVersionRemoved(version)
+	} else {
+		err = config.RemoveVersion(version)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to update config: %w", err)
 	}
 
+	// 清理指向该版本的命名 shim
+	for _, name := range links {
+		_ = vm.UnlinkVersion(name)
+	}
+	// 删除指向该版本的别名，避免 gvm use <别名> 指向已卸载的版本
+	for _, name := range aliases {
+		_ = config.RemoveAlias(name)
+	}
+
 	return nil
 }
+
+// uninstallBlocker 返回按 opts 卸载 version 时阻止卸载的错误，可以卸载时返回 nil。
+// uses 为 gvm 之外的使用（见 ExternalUses），links 与 aliases 为指向该版本的命名链接与别名。
+func (vm *VersionManager) uninstallBlocker(version string, uses, links, aliases []string, opts UninstallOptions) error {
+	// 检查是否是当前使用的版本
+	if vm.isCurrent(version) {
+		return newError(CodeVersionInUse, "cannot uninstall currently active version %s", version)
+	}
+	// gvm 之外的使用（手动设置的 GOROOT、正在运行的构建）不会记录在配置中，删除后这些终端会静默失效
+	if len(uses) > 0 && !opts.Force {
+		return newError(CodeVersionInUse, "version %s appears to be in use outside gvm: %s; close those shells or processes, or use --force",
+			version, summarizeUses(uses))
+	}
+	// 检查是否仍被命名链接引用，避免留下悬空的 shim
+	if len(links) > 0 && !opts.Force {
+		return newError(CodeVersionInUse, "version %s is still linked as %s; remove the links with 'gvm link --remove' or use --force",
+			version, strings.Join(links, ", "))
+	}
+	// 别名同样会在卸载后悬空
+	if len(aliases) > 0 && !opts.Force {
+		return newError(CodeVersionInUse, "version %s is the target of alias %s; remove the aliases with 'gvm alias --remove' or use --force",
+			version, strings.Join(aliases, ", "))
+	}
+	return nil
+}
+
+// isCurrent 判断 version 是否为当前版本：配置中记录的当前版本，或 PATH 中的 go 所属的版本
+func (vm *VersionManager) isCurrent(version string) bool {
+	if current, _ := config.GetCurrentVersion(); current == version {
+		return true
+	}
+	current, _ := vm.GetCurrentVersion()
+	return current == version
+}
+
+// UninstallPlan 描述按给定选项卸载某个版本时会发生什么，供 uninstall --dry-run 预览
+type UninstallPlan struct {
+	Version      string   `json:"version"`
+	Dir          string   `json:"dir"`
+	Size         int64    `json:"size"` // 删除后释放的字节数
+	Active       bool     `json:"active"`
+	Links        []string `json:"links"`         // 指向该版本的命名链接，卸载时一并删除
+	Aliases      []string `json:"aliases"`       // 指向该版本的别名，卸载时一并删除
+	ExternalUses []string `json:"external_uses"` // gvm 之外的使用，见 ExternalUses
+	KeepConfig   bool     `json:"keep_config"`
+	Allowed      bool     `json:"allowed"`          // 按给定选项实际卸载时是否会执行
+	Reason       string   `json:"reason,omitempty"` // 不会执行时的原因
+}
+
+// PlanUninstall 检查按 opts 卸载 version 的结果而不删除任何内容，与 UninstallVersionWithOptions 使用相同的判断
+func (vm *VersionManager) PlanUninstall(version string, opts UninstallOptions) (*UninstallPlan, error) {
+	installed, err := vm.IsVersionInstalled(version)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		return nil, newError(CodeVersionNotInstalled, "version %s is not installed", version)
+	}
+
+	dir, err := filepath.Abs(filepath.Join(vm.installDir, version))
+	if err != nil {
+		return nil, err
+	}
+	plan := &UninstallPlan{
+		Version:      version,
+		Dir:          dir,
+		Size:         dirSize(dir),
+		Active:       vm.isCurrent(version),
+		Links:        vm.LinksTo(version),
+		Aliases:      vm.AliasesTo(version),
+		ExternalUses: vm.ExternalUses(version),
+		KeepConfig:   opts.KeepConfig,
+	}
+	if plan.Links == nil {
+		plan.Links = []string{}
+	}
+	if plan.Aliases == nil {
+		plan.Aliases = []string{}
+	}
+	if plan.ExternalUses == nil {
+		plan.ExternalUses = []string{}
+	}
+	if err := vm.uninstallBlocker(version, plan.ExternalUses, plan.Links, plan.Aliases, opts); err != nil {
+		plan.Reason = err.Error()
+	} else {
+		plan.Allowed = true
+	}
+	return plan, nil
+}
+
+// dirSize 返回目录中普通文件的总大小，无法读取的条目被忽略
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// GetRemovedVersions 返回以 uninstall --keep-config 删除、仍保留记录的版本
+func (vm *VersionManager) GetRemovedVersions() ([]string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for v, info := range cfg.Versions {
+		if !info.Removed {
+			continue
+		}
+		if installed, _ := vm.IsVersionInstalled(v); !installed {
+			removed = append(removed, v)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return CompareVersions(removed[i], removed[j]) > 0 })
+	return removed, nil
+}
+
+// LinksTo 返回指向 version 的命名链接，按名称排序
+func (vm *VersionManager) LinksTo(version string) []string {
+	links, err := config.GetLinks()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for name, v := range links {
+		if v == version {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AliasesTo 返回指向 version 的别名（gvm alias），按名称排序
+func (vm *VersionManager) AliasesTo(version string) []string {
+	aliases, err := config.GetAliases()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for name, v := range aliases {
+		if v == version {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveLink 将命名链接解析为其指向的版本；name 本身是已安装版本或不是链接时返回 false
+func (vm *VersionManager) ResolveLink(name string) (string, bool) {
+	if installed, _ := vm.IsVersionInstalled(name); installed {
+		return "", false
+	}
+	links, err := config.GetLinks()
+	if err != nil {
+		return "", false
+	}
+	v, ok := links[name]
+	return v, ok
+}
+
+// AliasLatest 是内置别名，总是指向最新的稳定版本
+const AliasLatest = "latest"
+
+// ResolveAlias 将 gvm alias 定义的别名解析为其指向的版本，内置别名 latest 解析为最新的稳定版本；
+// name 本身是已安装版本或不是别名时返回 false
+func (vm *VersionManager) ResolveAlias(name string) (string, bool, error) {
+	if name == AliasLatest {
+		v, err := vm.GetLatestStable()
+		if err != nil {
+			return "", true, fmt.Errorf("failed to resolve latest version: %w", err)
+		}
+		return v, true, nil
+	}
+	if installed, _ := vm.IsVersionInstalled(name); installed {
+		return "", false, nil
+	}
+	v, ok := config.ResolveAlias(name)
+	return v, ok, nil
+}
+
+// LinkVersion 创建名为 name 的 shim，使指定版本可以通过该命令名直接调用。
+func (vm *VersionManager) LinkVersion(version, name string) error {
+	if name == "" || name == "go" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid link name %q", name)
+	}
+	installed, err := vm.IsVersionInstalled(version)
+	if err != nil {
+		return err
+	}
+	if !installed {
+		return newError(CodeVersionNotInstalled, "version %s is not installed", version)
+	}
+
+	goBinPath := filepath.Join(vm.installDir, version, "bin")
+	if err := utils.WriteNamedShim(name, goBinPath); err != nil {
+		return err
+	}
+	if err := config.AddLink(name, version); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	return nil
+}
+
+// UnlinkVersion 删除名为 name 的命名 shim。
+func (vm *VersionManager) UnlinkVersion(name string) error {
+	links, err := config.GetLinks()
+	if err != nil {
+		return err
+	}
+	if _, ok := links[name]; !ok {
+		return fmt.Errorf("link %s does not exist", name)
+	}
+	if err := utils.RemoveNamedShim(name); err != nil {
+		return err
+	}
+	if err := config.RemoveLink(name); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	return nil
+}
+
+// 安装后验证的重试次数与间隔
+const (
+	validateAttempts = 5
+	validateInterval = 200 * time.Millisecond
+)
+
+// validateInstallWithRetry 多次尝试验证安装结果，用于规避 NFS/overlay 等文件系统刚写入后的短暂不可见
+func validateInstallWithRetry(installPath, version string, checkVersion bool) error {
+	var err error
+	for i := 0; i < validateAttempts; i++ {
+		if err = validateInstall(installPath, version, checkVersion); err == nil {
+			return nil
+		}
+		time.Sleep(time.Duration(i+1) * validateInterval)
+	}
+	return err
+}
+
+// validateInstall 检查 go 二进制是否存在；checkVersion 为 true 时还要求 VERSION 文件与版本号一致
+func validateInstall(installPath, version string, checkVersion bool) error {
+	if checkVersion {
+		verFile := filepath.Join(installPath, "VERSION")
+		b, err := os.ReadFile(verFile)
+		if err != nil {
+			return fmt.Errorf("validation failed: missing VERSION: %w", err)
+		}
+		// Go 1.21 起 VERSION 在版本号之后还有 time ... 等构建信息行，只比较第一行
+		installedVer := versionLine(string(b))
+		if installedVer != version {
+			return fmt.Errorf("validation failed: version mismatch: expected %s got %s", version, installedVer)
+		}
+	}
+	if _, err := os.Stat(goBinary(installPath)); err != nil {
+		return fmt.Errorf("validation failed: go binary missing: %w", err)
+	}
+	return nil
+}
+
+// versionLine 返回 VERSION 文件内容中第一个非空行（版本号），与 list 检测系统 Go 的方式一致
+func versionLine(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// DefaultValidateTimeout 是安装后执行 `go version` 验证的默认超时时间
+const DefaultValidateTimeout = 30 * time.Second
+
+// runGoVersion 在 timeout 内执行安装目录中的 `go version`。失败时错误中包含捕获的
+// stdout/stderr，例如 musl 上的动态链接器错误或架构不匹配时的 "bad CPU type"。
+func runGoVersion(installPath string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultValidateTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, goBinary(installPath), "version")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("validation failed: 'go version' did not finish within %s%s", timeout, capturedOutput(stdout.String(), stderr.String()))
+	}
+	if err != nil {
+		return fmt.Errorf("validation failed: 'go version' failed: %w%s", err, capturedOutput(stdout.String(), stderr.String()))
+	}
+	return nil
+}
+
+// capturedOutput 将命令输出格式化为附加在错误信息后的诊断内容
+func capturedOutput(stdout, stderr string) string {
+	var b strings.Builder
+	if s := strings.TrimSpace(stdout); s != "" {
+		b.WriteString("\nstdout: " + s)
+	}
+	if s := strings.TrimSpace(stderr); s != "" {
+		b.WriteString("\nstderr: " + s)
+	}
+	return b.String()
+}
+
+// goBinary 返回安装目录下 go 可执行文件的路径
+func goBinary(installPath string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(installPath, "bin", "go.exe")
+	}
+	return filepath.Join(installPath, "bin", "go")
+}
+
+// ArchiveFilename 返回指定版本在当前平台上的规范安装包文件名，例如 go1.4.3.linux-amd64.tar.gz
+func ArchiveFilename(version string) string {
+	ext := "tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("%s.%s-%s.%s", version, runtime.GOOS, runtime.GOARCH, ext)
+}
+
+// archivedVersion 为 JSON 中不存在的版本构造安装信息。
+// 由于无法从 JSON 获得校验值，必须提供 checksum 或能从镜像获取 .sha256 旁路文件。
+func (vm *VersionManager) archivedVersion(version, checksum string) (*GoVersion, error) {
+	filename := ArchiveFilename(version)
+	if checksum == "" {
+		sum, err := vm.fetchSidecarChecksum(filename, vm.baseURLs()...)
+		if err != nil {
+			return nil, newError(CodeVersionNotFound, "version %s is not in the versions JSON and no checksum is available (pass --checksum): %w", version, err)
+		}
+		checksum = sum
+	}
+	return &GoVersion{
+		Version: version,
+		Stable:  true,
+		Files: []GoFile{{
+			Filename: filename,
+			OS:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			Version:  version,
+			SHA256:   checksum,
+			Kind:     "archive",
+		}},
+	}, nil
+}
+
+// fetchSidecarChecksum 依次从 bases 下载 <filename>.sha256 旁路文件并返回其中的摘要
+func (vm *VersionManager) fetchSidecarChecksum(filename string, bases ...string) (string, error) {
+	client, err := utils.NewHTTPClient(vm.settings.HTTPTimeout, vm.settings.Proxy, vm.settings.RedirectHosts)
+	if err != nil {
+		return "", err
+	}
+	var lastErr error
+	for _, base := range bases {
+		url := fmt.Sprintf("%s/dl/%s.sha256", base, filename)
+		newRequest := utils.NewRequest
+		if base == OfficialBaseURL {
+			// 校验值的权威来源不属于镜像，不带私有镜像的凭据
+			newRequest = utils.NewPublicRequest
+		}
+		req, err := newRequest(url)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		utils.LogFinalURL(resp, url)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("bad status: %s", resp.Status)
+			continue
+		}
+		fields := strings.Fields(string(body))
+		if len(fields) == 0 || len(fields[0]) != 64 {
+			lastErr = fmt.Errorf("malformed checksum file from %s", url)
+			continue
+		}
+		return strings.ToLower(fields[0]), nil
+	}
+	return "", fmt.Errorf("failed to fetch checksum for %s: %w", filename, lastErr)
+}
+
+// supportedPlatforms 返回版本提供安装包的 os-arch 组合（去重并排序）
+func supportedPlatforms(v *GoVersion) []string {
+	seen := make(map[string]bool)
+	platforms := []string{}
+	for _, f := range v.Files {
+		if !f.IsArchive() || f.OS == "" || f.Arch == "" {
+			continue
+		}
+		p := f.OS + "-" + f.Arch
+		if !seen[p] {
+			seen[p] = true
+			platforms = append(platforms, p)
+		}
+	}
+	sort.Strings(platforms)
+	if len(platforms) == 0 {
+		platforms = append(platforms, "none")
+	}
+	return platforms
+}
+
+// CheckVersion 检查已安装版本是否完整可用：VERSION 与二进制存在且 `go version` 可以执行。
+func (vm *VersionManager) CheckVersion(version string) error {
+	installPath := filepath.Join(vm.installDir, version)
+	// 以 --no-validate 安装的版本不要求 VERSION 与版本号一致
+	checkVersion := true
+	if cfg, err := config.Load(); err == nil {
+		checkVersion = !cfg.Versions[version].Unvalidated
+	}
+	if err := validateInstall(installPath, version, checkVersion); err != nil {
+		return err
+	}
+	return runGoVersion(installPath, DefaultValidateTimeout)
+}
+
+// Series 返回版本所属的次版本系列，例如 go1.21.5 -> go1.21
+func Series(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "go"), ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	minor := parts[1]
+	// 去除 rc/beta 后缀，例如 1.22rc1 -> 22
+	for i, c := range minor {
+		if c < '0' || c > '9' {
+			minor = minor[:i]
+			break
+		}
+	}
+	return "go" + parts[0] + "." + minor
+}
+
+// GetVersionDir 返回指定版本的安装目录。
+func (vm *VersionManager) GetVersionDir(version string) string {
+	return filepath.Join(vm.installDir, version)
+}
+
+// ListTools 返回已安装版本 bin/ 目录下的可执行文件名（去除 .exe 后缀并排序）。
+func (vm *VersionManager) ListTools(version string) ([]string, error) {
+	installed, err := vm.IsVersionInstalled(version)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		return nil, newError(CodeVersionNotInstalled, "version %s is not installed", version)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(vm.installDir, version, "bin"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bin directory: %w", err)
+	}
+	tools := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		tools = append(tools, strings.TrimSuffix(entry.Name(), ".exe"))
+	}
+	sort.Strings(tools)
+	return tools, nil
+}
@@ -0,0 +1,147 @@
+package version
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VersionFileName 是项目中声明 Go 版本的文件名，goenv、asdf 以及 CI 的 setup-go 等工具都会读取它
+const VersionFileName = ".go-version"
+
+// FindVersionFile 从 dir 开始逐级向上查找 .go-version，返回找到的第一个文件的路径
+func FindVersionFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for start := dir; ; {
+		path := filepath.Join(dir, VersionFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s file found in %s or any parent directory", VersionFileName, start)
+		}
+		dir = parent
+	}
+}
+
+// ParseVersionFile 读取 .go-version 等版本文件，返回规范形式的版本号（如 go1.21.5）。
+// 文件中第一行非空、且不以 # 开头的内容为版本，接受 1.21.5、go1.21.5、v1.21.5 与 1.22rc1 等形式；
+// 只有主次版本号时（如 1.21）视为系列，series 为 true，需要再用 ResolveSeries 确定补丁版本。
+func ParseVersionFile(path string) (version string, series bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		v, series, err := parseVersionSpec(line)
+		if err != nil {
+			return "", false, fmt.Errorf("%s: %w", path, err)
+		}
+		return v, series, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return "", false, fmt.Errorf("no Go version found in %s", path)
+}
+
+// parseVersionSpec 规范化版本文件中的一个版本，见 ParseVersionFile
+func parseVersionSpec(spec string) (string, bool, error) {
+	v := strings.TrimPrefix(spec, "v")
+	s, err := ParseSemVer(v)
+	if err != nil {
+		return "", false, err
+	}
+	switch strings.Count(strings.TrimPrefix(s.Version, "go"), ".") {
+	case 0:
+		return "", false, fmt.Errorf("invalid Go version %q: expected at least a major and minor version such as 1.21", spec)
+	case 1:
+		if s.Prerelease == "" {
+			return s.Series, true, nil
+		}
+	}
+	return s.Version, false, nil
+}
+
+// ResolveSeries 返回系列（如 go1.21）中已安装的最新版本；没有已安装的版本时返回可用版本列表中该系列最新的正式版本
+func (vm *VersionManager) ResolveSeries(series string) (string, error) {
+	installed, err := vm.GetInstalledVersions()
+	if err != nil {
+		return "", err
+	}
+	if v := latestInSeries(installed, series); v != "" {
+		return v, nil
+	}
+
+	v, err := vm.ResolveSeriesAvailable(series)
+	if err != nil {
+		return "", fmt.Errorf("no %s version is installed: %w", series, err)
+	}
+	return v, nil
+}
+
+// ResolveSeriesAvailable 返回可用版本列表中系列最新的正式版本，不考虑已安装的版本；用于安装
+func (vm *VersionManager) ResolveSeriesAvailable(series string) (string, error) {
+	available, err := vm.GetAvailableVersions()
+	if err != nil {
+		return "", fmt.Errorf("the available versions could not be fetched: %w", err)
+	}
+	var stable []string
+	for _, v := range available {
+		if v.Stable {
+			stable = append(stable, v.Version)
+		}
+	}
+	if v := latestInSeries(stable, series); v != "" {
+		return v, nil
+	}
+	return "", newError(CodeVersionNotFound, "no %s release is available", series)
+}
+
+// resolveSeries 在 preferInstalled 为 true 时使用 ResolveSeries（切换版本），否则使用 ResolveSeriesAvailable（安装）
+func (vm *VersionManager) resolveSeries(series string, preferInstalled bool) (string, error) {
+	if preferInstalled {
+		return vm.ResolveSeries(series)
+	}
+	return vm.ResolveSeriesAvailable(series)
+}
+
+// latestInSeries 返回 versions 中属于 series 的最新版本，没有时返回空字符串
+func latestInSeries(versions []string, series string) string {
+	latest := ""
+	for _, v := range versions {
+		if Series(v) != series {
+			continue
+		}
+		if latest == "" || CompareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// ResolveVersionFile 读取版本文件并确定要使用的版本，系列会解析为具体的补丁版本：
+// preferInstalled 为 true 时优先使用已安装的补丁版本，否则使用可用的最新补丁版本
+func (vm *VersionManager) ResolveVersionFile(path string, preferInstalled bool) (string, error) {
+	v, series, err := ParseVersionFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !series {
+		return v, nil
+	}
+	return vm.resolveSeries(v, preferInstalled)
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/philokun/gvm/internal/utils"
+)
+
+// buildGVM 将 gvm 编译到临时目录，用于按用户的方式运行命令；应在修改 HOME 之前调用以使用构建缓存
+func buildGVM(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "gvm")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	build := exec.Command("go", "build", "-o", bin, "github.com/philokun/gvm")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	return bin
+}
+
+// runGVM 在测试镜像与临时 HOME 下运行 gvm，返回合并的输出
+func runGVM(t *testing.T, bin string, m *testMirror, args ...string) (string, error) {
+	t.Helper()
+	c := exec.Command(bin, args...)
+	c.Env = append(os.Environ(), "GVM_DL_MIRROR="+m.srv.URL, "GVM_ALT_MIRROR=off", "CI=true")
+	out, err := c.CombinedOutput()
+	return string(out), err
+}
+
+func TestInstallCommandSrcFlags(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	bin := buildGVM(t)
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	srcDir := func() string {
+		return filepath.Join(os.Getenv("HOME"), ".gvm", "versions", "go1.98.2", "src")
+	}
+
+	for _, args := range [][]string{{"--no-src"}, {"--with-src=false"}} {
+		out, err := runGVM(t, bin, m, append([]string{"install", "go1.98.2", "--no-activate"}, args...)...)
+		if err != nil {
+			t.Fatalf("install %v: %v\n%s", args, err, out)
+		}
+		if utils.FileExists(srcDir()) {
+			t.Errorf("install %v extracted src/", args)
+		}
+		if out, err := runGVM(t, bin, m, "uninstall", "go1.98.2"); err != nil {
+			t.Fatalf("uninstall: %v\n%s", err, out)
+		}
+	}
+
+	out, err := runGVM(t, bin, m, "install", "go1.98.2", "--no-activate")
+	if err != nil {
+		t.Fatalf("install: %v\n%s", err, out)
+	}
+	if !utils.FileExists(srcDir()) {
+		t.Error("install without flags did not extract src/")
+	}
+
+	out, err = runGVM(t, bin, m, "install", "go1.98.2", "--with-src", "--no-src")
+	if err == nil || !strings.Contains(out, "cannot be used together") {
+		t.Errorf("install --with-src --no-src: err = %v, output:\n%s", err, out)
+	}
+}
+
+func TestUsePrintPathResolvesNames(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	bin := buildGVM(t)
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	if out, err := runGVM(t, bin, m, "install", "go1.98.2", "--no-activate"); err != nil {
+		t.Fatalf("install: %v\n%s", err, out)
+	}
+	want := filepath.Join(os.Getenv("HOME"), ".gvm", "versions", "go1.98.2", "bin")
+	for _, setup := range [][]string{{"alias", "prod", "1.98.2"}, {"link", "go1.98.2", "go198"}} {
+		if out, err := runGVM(t, bin, m, setup...); err != nil {
+			t.Fatalf("%v: %v\n%s", setup, err, out)
+		}
+	}
+
+	// --print-path 与 gvm use 一样接受别名、命名链接与 list --index 的编号
+	for _, name := range []string{"prod", "go198", "1"} {
+		out, err := runGVM(t, bin, m, "use", "--print-path", name)
+		if err != nil {
+			t.Fatalf("use --print-path %s: %v\n%s", name, err, out)
+		}
+		if got := strings.TrimSpace(out); got != want {
+			t.Errorf("use --print-path %s = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestEnvironmentTakesPrecedenceOverFlags(t *testing.T) {
+	bin := buildGVM(t)
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+
+	// runGVM 设置了 GVM_DL_MIRROR，指向无法连接地址的 --mirror 不生效
+	out, err := runGVM(t, bin, m, "available", "--refresh-cache", "--mirror", "http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("available --mirror: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "1.98.2") {
+		t.Errorf("available did not list the versions from GVM_DL_MIRROR:\n%s", out)
+	}
+}
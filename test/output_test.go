@@ -1,6 +1,9 @@
 package test
 
-import "testing"
+import (
+	"runtime"
+	"testing"
+)
 
 import "github.com/philokun/gvm/internal/output"
 
@@ -28,3 +31,69 @@ func TestPrintTableHeader(t *testing.T) {
 func TestSpinner(t *testing.T) {
 	output.Spinner("Installing Go 1.19.4...")
 }
+
+func TestParseFormat(t *testing.T) {
+	f, err := output.ParseFormat("", output.FormatPlain)
+	if err != nil || f != output.FormatPlain {
+		t.Fatalf("expected default plain, got %q (%v)", f, err)
+	}
+	f, err = output.ParseFormat("JSON", output.FormatTable)
+	if err != nil || f != output.FormatJSON {
+		t.Fatalf("expected json, got %q (%v)", f, err)
+	}
+	if _, err := output.ParseFormat("yaml", output.FormatTable); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestSetASCII(t *testing.T) {
+	defer output.SetASCII(false)
+	output.SetASCII(true)
+	for _, s := range []string{output.ColorRed, output.ColorReset, output.SymbolSuccess, output.SymbolError, output.SymbolArrow} {
+		for _, r := range s {
+			if r > 127 || r == '\033' {
+				t.Fatalf("%q is not plain ASCII", s)
+			}
+		}
+	}
+	output.SetASCII(false)
+	if output.SymbolSuccess != "✓" || output.ColorRed != "\033[31m" {
+		t.Fatalf("SetASCII(false) did not restore the defaults: %q %q", output.SymbolSuccess, output.ColorRed)
+	}
+}
+
+func TestSetASCIISymbolsKeepsColors(t *testing.T) {
+	defer output.SetASCII(false)
+	output.SetASCII(false)
+	output.SetASCIISymbols(true)
+	if output.SymbolSuccess != "[OK]" || output.SymbolError != "[ERR]" || output.SymbolWarning != "[WARN]" {
+		t.Errorf("symbols = %q %q %q", output.SymbolSuccess, output.SymbolError, output.SymbolWarning)
+	}
+	if output.ColorRed != "\033[31m" {
+		t.Errorf("SetASCIISymbols changed the colors: %q", output.ColorRed)
+	}
+}
+
+func TestSupportsUTF8(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows checks the console code page")
+	}
+	for _, tc := range []struct {
+		lcAll, lang string
+		want        bool
+	}{
+		{"", "en_US.UTF-8", true},
+		{"", "C.utf8", true},
+		{"", "de_DE.UTF-8@euro", true},
+		{"C", "en_US.UTF-8", false},
+		{"", "en_US.ISO-8859-1", false},
+		{"", "", false},
+	} {
+		t.Setenv("LC_ALL", tc.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tc.lang)
+		if got := output.SupportsUTF8(); got != tc.want {
+			t.Errorf("LC_ALL=%q LANG=%q: SupportsUTF8() = %v, want %v", tc.lcAll, tc.lang, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,656 @@
+package test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/philokun/gvm/internal/config"
+	"github.com/philokun/gvm/internal/utils"
+	"github.com/philokun/gvm/internal/version"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// testMirror 是本地的 go.dev 风格镜像：/dl/?mode=json 返回构造的版本列表，
+// /dl/<文件名> 返回只包含 VERSION、假 go 程序与一个 src 文件的小安装包
+type testMirror struct {
+	srv       *httptest.Server
+	versions  []version.GoVersion
+	archives  map[string][]byte // 文件名 -> 安装包内容
+	downloads int32             // 安装包被下载的次数
+}
+
+// newTestMirror 启动测试镜像，并将 HOME 指向临时目录，使缓存、锁与 config.json 都写入其中
+func newTestMirror(t *testing.T) *testMirror {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	m := &testMirror{archives: make(map[string][]byte)}
+	m.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dl/" && r.URL.Query().Get("mode") == "json" {
+			_ = json.NewEncoder(w).Encode(m.versions)
+			return
+		}
+		body, ok := m.archives[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&m.downloads, 1)
+		http.ServeContent(w, r, filepath.Base(r.URL.Path), time.Time{}, bytes.NewReader(body))
+	}))
+	t.Cleanup(m.srv.Close)
+	return m
+}
+
+// addVersion 向版本列表追加一个版本，并为当前平台生成安装包（列表按添加顺序返回，应先添加新版本）
+func (m *testMirror) addVersion(t *testing.T, v string, stable bool) version.GoFile {
+	t.Helper()
+	archive := fakeGoArchive(t, v)
+	sum := sha256.Sum256(archive)
+	f := version.GoFile{
+		Filename: fmt.Sprintf("%s.%s-%s.tar.gz", v, runtime.GOOS, runtime.GOARCH),
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Version:  v,
+		SHA256:   hex.EncodeToString(sum[:]),
+		Size:     len(archive),
+		Kind:     "archive",
+	}
+	m.archives[f.Filename] = archive
+	m.versions = append(m.versions, version.GoVersion{Version: v, Stable: stable, Files: []version.GoFile{f}})
+	return f
+}
+
+// manager 返回只使用测试镜像的 VersionManager，安装到临时目录
+func (m *testMirror) manager(t *testing.T) *version.VersionManager {
+	return version.NewWithSettings(filepath.Join(t.TempDir(), "versions"), config.Settings{
+		Mirror:      m.srv.URL,
+		HTTPTimeout: 5 * time.Second,
+		Downloader:  config.DefaultDownloader,
+		Source:      config.SourceGoDev,
+	})
+}
+
+// fakeGoArchive 构造一个能通过安装验证的 .tar.gz：VERSION、输出版本号的 go 脚本与 src/README（目录条目与官方安装包一样在前）
+func fakeGoArchive(t *testing.T, v string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	files := []struct {
+		name string
+		mode int64
+		body string
+	}{
+		{"go/VERSION", 0644, v + "\ntime 2024-01-01T00:00:00Z\n"},
+		{"go/bin/go", 0755, fmt.Sprintf("#!/bin/sh\necho go version %s %s/%s\n", v, runtime.GOOS, runtime.GOARCH)},
+		{"go/src/README", 0644, "standard library sources\n"},
+	}
+	for _, dir := range []string{"go/", "go/bin/", "go/src/"} {
+		if err := tw.WriteHeader(&tar.Header{Name: dir, Mode: 0755, Typeflag: tar.TypeDir}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: f.mode, Size: int64(len(f.body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(f.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGetAvailableVersionsFromMirror(t *testing.T) {
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.99rc1", false)
+	m.addVersion(t, "go1.98.2", true)
+	m.addVersion(t, "go1.98.1", true)
+	vm := m.manager(t)
+
+	versions, source, err := vm.LoadAvailableVersions(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 3 || versions[0].Version != "go1.99rc1" {
+		t.Fatalf("versions = %+v", versions)
+	}
+	if source.FromCache || source.Mirror != m.srv.URL {
+		t.Errorf("source = %+v, want a fresh list from %s", source, m.srv.URL)
+	}
+
+	latest, err := vm.GetLatestStable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "go1.98.2" {
+		t.Errorf("GetLatestStable() = %s, want go1.98.2", latest)
+	}
+}
+
+func TestInstallFromMirror(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	vm := m.manager(t)
+
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.CheckVersion("go1.98.2"); err != nil {
+		t.Error(err)
+	}
+	if n := atomic.LoadInt32(&m.downloads); n != 1 {
+		t.Errorf("archive downloaded %d times, want 1", n)
+	}
+
+	// 重装时使用下载缓存中校验通过的安装包
+	if err := vm.UninstallVersion("go1.98.2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&m.downloads); n != 1 {
+		t.Errorf("archive downloaded %d times after reinstalling, want 1 (from the cache)", n)
+	}
+}
+
+func TestInstallFromMirrorChecksumMismatch(t *testing.T) {
+	m := newTestMirror(t)
+	f := m.addVersion(t, "go1.98.2", true)
+	// 镜像提供的安装包与版本列表中的校验值不一致
+	m.archives[f.Filename] = append([]byte(nil), m.archives[f.Filename]...)
+	m.archives[f.Filename][len(m.archives[f.Filename])-1] ^= 0xff
+	vm := m.manager(t)
+
+	err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{})
+	if !errors.Is(err, version.ErrChecksum) {
+		t.Fatalf("err = %v, want ErrChecksum", err)
+	}
+	if installed, _ := vm.IsVersionInstalled("go1.98.2"); installed {
+		t.Error("a version whose archive failed verification was installed")
+	}
+	quarantined, _ := filepath.Glob(filepath.Join(os.Getenv("HOME"), ".gvm", "cache", "quarantine", f.Filename+".*.mismatch"))
+	if len(quarantined) != 1 {
+		t.Errorf("quarantined files = %v, want one", quarantined)
+	}
+}
+
+func TestInstallChecksumRequiresListedVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	m := newTestMirror(t)
+	listed := m.addVersion(t, "go1.98.2", true)
+	// go1.97.9 可以从镜像下载，但不在版本 JSON 中
+	archive := fakeGoArchive(t, "go1.97.9")
+	sum := sha256.Sum256(archive)
+	m.archives[fmt.Sprintf("go1.97.9.%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)] = archive
+	vm := m.manager(t)
+
+	// --checksum 直接下载列表中的版本
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{Checksum: listed.SHA256}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不在列表中的版本仍需要 --archived
+	opts := version.InstallOptions{Checksum: hex.EncodeToString(sum[:])}
+	err := vm.InstallVersionWithOptions("go1.97.9", opts)
+	var verr *version.Error
+	if !errors.As(err, &verr) || verr.Code != version.CodeVersionNotFound {
+		t.Fatalf("install of an unlisted version with --checksum: err = %v, want %s", err, version.CodeVersionNotFound)
+	}
+	if installed, _ := vm.IsVersionInstalled("go1.97.9"); installed {
+		t.Fatal("the unlisted version was installed without --archived")
+	}
+	opts.Archived = true
+	if err := vm.InstallVersionWithOptions("go1.97.9", opts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallFinishesInterruptedInstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	vm := m.manager(t)
+
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟在版本目录移动到位之后、写入配置之前被终止的安装：暂存目录中的标记文件仍在版本目录中
+	if err := config.RemoveVersion("go1.98.2"); err != nil {
+		t.Fatal(err)
+	}
+	marker := filepath.Join(vm.GetVersionDir("go1.98.2"), ".gvm-staged")
+	if err := os.WriteFile(marker, []byte(`{"archive":"go1.98.2.tar.gz","sha256":""}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{}); err != nil {
+		t.Fatalf("installing again after the interruption: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Versions["go1.98.2"]; !ok {
+		t.Error("the interrupted install was not recorded in the config")
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("marker left in the version directory: %v", err)
+	}
+	if n := atomic.LoadInt32(&m.downloads); n != 1 {
+		t.Errorf("archive downloaded %d times, want 1", n)
+	}
+
+	// 没有标记也没有配置记录的目录不是 gvm 安装的
+	if err := config.RemoveVersion("go1.98.2"); err != nil {
+		t.Fatal(err)
+	}
+	err = vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{})
+	var verr *version.Error
+	if !errors.As(err, &verr) || verr.Code != version.CodeForeignInstall {
+		t.Errorf("err = %v, want %s", err, version.CodeForeignInstall)
+	}
+	if err := config.AddVersion("go1.98.2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 配置中已有记录时仍然报告已安装
+	err = vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{})
+	if !errors.As(err, &verr) || verr.Code != version.CodeAlreadyInstalled {
+		t.Errorf("err = %v, want %s", err, version.CodeAlreadyInstalled)
+	}
+}
+
+func TestResolveAlias(t *testing.T) {
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	vm := m.manager(t)
+
+	if err := config.SetAlias("prod", "go1.97.1"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := vm.ResolveAlias("prod"); err != nil || !ok || v != "go1.97.1" {
+		t.Errorf("ResolveAlias(prod) = %q, %v, %v; want go1.97.1", v, ok, err)
+	}
+	if _, ok, _ := vm.ResolveAlias("staging"); ok {
+		t.Error("an undefined name resolved as an alias")
+	}
+	// 内置的 latest 总是解析为最新的稳定版本
+	if v, ok, err := vm.ResolveAlias(version.AliasLatest); err != nil || !ok || v != "go1.98.2" {
+		t.Errorf("ResolveAlias(latest) = %q, %v, %v; want go1.98.2", v, ok, err)
+	}
+
+	if err := config.RemoveAlias("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := vm.ResolveAlias("prod"); ok {
+		t.Error("a removed alias still resolves")
+	}
+	if err := config.RemoveAlias("prod"); err == nil {
+		t.Error("removing an undefined alias succeeded")
+	}
+}
+
+func TestUninstallKeepsAliasedVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	vm := m.manager(t)
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetAlias("prod", "go1.98.2"); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := vm.PlanUninstall("go1.98.2", version.UninstallOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Allowed || len(plan.Aliases) != 1 || plan.Aliases[0] != "prod" {
+		t.Errorf("PlanUninstall = allowed %v, aliases %v; want refused because of prod", plan.Allowed, plan.Aliases)
+	}
+	if err := vm.UninstallVersion("go1.98.2"); err == nil {
+		t.Fatal("uninstalled a version that an alias points to")
+	}
+	if installed, _ := vm.IsVersionInstalled("go1.98.2"); !installed {
+		t.Fatal("the refused uninstall removed the version")
+	}
+
+	// --force 同时删除别名，不留下指向已卸载版本的别名
+	if err := vm.UninstallVersionWithOptions("go1.98.2", version.UninstallOptions{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.ResolveAlias("prod"); ok {
+		t.Error("the alias of the uninstalled version was kept")
+	}
+}
+
+func TestResolveGoModSeries(t *testing.T) {
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	m.addVersion(t, "go1.98.1", true)
+	m.addVersion(t, "go1.97.5", true)
+	vm := m.manager(t)
+
+	dir := t.TempDir()
+	cases := []struct {
+		content string
+		want    string
+	}{
+		// 只有主次版本号时使用该系列最新的补丁版本
+		{"module example.com/m\n\ngo 1.98\n", "go1.98.2"},
+		{"module example.com/m\n\ngo 1.98.1\n", "go1.98.1"},
+		{"module example.com/m\n\ngo 1.98\n\ntoolchain go1.97.5\n", "go1.97.5"},
+	}
+	for i, c := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("go%d.mod", i))
+		if err := os.WriteFile(path, []byte(c.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := vm.ResolveGoMod(path, false)
+		if err != nil {
+			t.Fatalf("ResolveGoMod(%q): %v", c.content, err)
+		}
+		if got != c.want {
+			t.Errorf("ResolveGoMod(%q) = %q, want %q", c.content, got, c.want)
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return // the fake go binary is a shell script
+	}
+	// 已安装旧的补丁版本时：安装仍使用最新的补丁版本，切换优先使用已安装的版本
+	if err := vm.InstallVersionWithOptions("go1.98.1", version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "go0.mod")
+	if got, err := vm.ResolveGoMod(path, false); err != nil || got != "go1.98.2" {
+		t.Errorf("ResolveGoMod(go 1.98, install) = %q, %v, want go1.98.2", got, err)
+	}
+	if got, err := vm.ResolveGoMod(path, true); err != nil || got != "go1.98.1" {
+		t.Errorf("ResolveGoMod(go 1.98, use) = %q, %v, want the installed go1.98.1", got, err)
+	}
+}
+
+func TestMoveInstallDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	vm := m.manager(t)
+	if err := vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	newDir := filepath.Join(t.TempDir(), "big", "versions")
+	moved, err := vm.MoveInstallDir(newDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 1 || moved[0] != "go1.98.2" {
+		t.Errorf("moved = %v, want [go1.98.2]", moved)
+	}
+	// New 使用 config.json 中的 install_dir
+	if dir := version.New().GetInstallDir(); dir != newDir {
+		t.Errorf("New().GetInstallDir() = %s, want %s", dir, newDir)
+	}
+	if err := version.New().CheckVersion("go1.98.2"); err != nil {
+		t.Errorf("the moved version is broken: %v", err)
+	}
+
+	// GVM_HOME 优先于 install_dir
+	override := t.TempDir()
+	t.Setenv("GVM_HOME", override)
+	if dir := version.New().GetInstallDir(); dir != override {
+		t.Errorf("New().GetInstallDir() with GVM_HOME = %s, want %s", dir, override)
+	}
+}
+
+func TestInstallForceWaitsForLock(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	m := newTestMirror(t)
+	m.addVersion(t, "go1.98.2", true)
+	vm := m.manager(t)
+
+	// 另一个进程持有锁时，目标目录中的非 gvm 安装不能被 --force 删除
+	foreign := filepath.Join(vm.GetInstallDir(), "go1.98.2")
+	if err := os.MkdirAll(foreign, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(foreign, "VERSION"), []byte("go1.98.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock, _, err := utils.AcquireLock("go1.98.2", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- vm.InstallVersionWithOptions("go1.98.2", version.InstallOptions{Force: true}) }()
+	time.Sleep(300 * time.Millisecond)
+	if !utils.FileExists(filepath.Join(foreign, "VERSION")) {
+		t.Error("--force removed the target while another process held the lock")
+	}
+	lock.Release()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.CheckVersion("go1.98.2"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMirrorHeadersStayOnMirror(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GVM_HTTP_HEADERS", "Authorization: Bearer secret; X-Jfrog-Art-Api: key")
+	t.Setenv("GVM_HTTP_HEADERS_HOSTS", "")
+
+	// leaked 记录收到私有镜像请求头的其他服务器
+	var mu sync.Mutex
+	var leaked []string
+	record := func(name string, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Jfrog-Art-Api") != "" {
+			mu.Lock()
+			leaked = append(leaked, name+" "+r.URL.Path)
+			mu.Unlock()
+		}
+	}
+	const v = "go1.98.2"
+	archive := fakeGoArchive(t, v)
+	sum := sha256.Sum256(archive)
+	filename := fmt.Sprintf("%s.%s-%s.tar.gz", v, runtime.GOOS, runtime.GOARCH)
+
+	// 优先镜像（默认为 golang.google.cn）拦截版本 JSON 接口
+	alt := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record("alt mirror", r)
+		http.NotFound(w, r)
+	}))
+	defer alt.Close()
+	// 安装包由镜像重定向到 go.dev
+	godev := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record("go.dev", r)
+		http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(archive))
+	}))
+	defer godev.Close()
+	var mirrorAuthorized int32
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&mirrorAuthorized, 1)
+		if r.URL.Path == "/dl/" {
+			_ = json.NewEncoder(w).Encode([]version.GoVersion{{Version: v, Stable: true, Files: []version.GoFile{{
+				Filename: filename, OS: runtime.GOOS, Arch: runtime.GOARCH, Version: v,
+				SHA256: hex.EncodeToString(sum[:]), Size: len(archive), Kind: "archive",
+			}}}})
+			return
+		}
+		http.Redirect(w, r, godev.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer mirror.Close()
+
+	t.Setenv("GVM_DL_MIRROR", mirror.URL)
+	t.Setenv("GVM_ALT_MIRROR", alt.URL)
+	vm := version.NewWithSettings(filepath.Join(t.TempDir(), "versions"), config.ResolveSettings())
+	if err := vm.InstallVersionWithOptions(v, version.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&mirrorAuthorized) == 0 {
+		t.Error("the configured mirror never received the headers")
+	}
+	if len(leaked) != 0 {
+		t.Errorf("the mirror headers were sent to %v", leaked)
+	}
+}
+
+// fakeToolchainZip 构造 golang.org/toolchain 模块 zip，内容与 fakeGoArchive 相同
+func fakeToolchainZip(t *testing.T, v, modVersion string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	prefix := fmt.Sprintf("%s@%s/", version.ToolchainModule, modVersion)
+	files := map[string]string{
+		"VERSION":    v + "\ntime 2024-01-01T00:00:00Z\n",
+		"bin/go":     fmt.Sprintf("#!/bin/sh\necho go version %s %s/%s\n", v, runtime.GOOS, runtime.GOARCH),
+		"src/README": "standard library sources\n",
+	}
+	for name, body := range files {
+		w, err := zw.Create(prefix + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGoProxyVerifiesSignedChecksums(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake go binary is a shell script")
+	}
+	const v = "go1.98.2"
+	modVersion := fmt.Sprintf("v0.0.1-%s.%s-%s", v, runtime.GOOS, runtime.GOARCH)
+	archive := fakeToolchainZip(t, v, modVersion)
+	zipPath := filepath.Join(t.TempDir(), "toolchain.zip")
+	if err := os.WriteFile(zipPath, archive, 0644); err != nil {
+		t.Fatal(err)
+	}
+	h1, err := utils.ZipHash1(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sumDB 返回一个用 skey 签名、记录 sum 作为工具链摘要的校验和数据库
+	sumDB := func(skey, sum string) http.Handler {
+		return sumdb.NewServer(sumdb.NewTestServer(skey, func(path, vers string) ([]byte, error) {
+			return []byte(fmt.Sprintf("%s %s %s\n%s %s/go.mod h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=\n", path, vers, sum, path, vers)), nil
+		}))
+	}
+	skey, vkey, err := note.GenerateKey(rand.Reader, "sum.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := note.GenerateKey(rand.Reader, "sum.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s %s\n", version.ToolchainModule, modVersion, h1)
+	})
+
+	tests := []struct {
+		name   string
+		sumdb  http.Handler // GOPROXY 的 /sumdb/sum.example.com/ 接口
+		wantOK bool
+	}{
+		{"signed", sumDB(skey, h1), true},
+		{"signed with a different hash", sumDB(skey, "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="), false},
+		{"signed with another key", sumDB(otherKey, h1), false},
+		{"unsigned lookup", unsigned, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTestMirror(t)
+			proxy := http.NewServeMux()
+			proxy.Handle("/sumdb/sum.example.com/", http.StripPrefix("/sumdb/sum.example.com", tt.sumdb))
+			proxy.HandleFunc("/"+version.ToolchainModule+"/@v/", func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "toolchain.zip", time.Time{}, bytes.NewReader(archive))
+			})
+			srv := httptest.NewServer(proxy)
+			defer srv.Close()
+			// 直接访问的地址不可用，只能通过 GOPROXY 查询
+			t.Setenv("GOPROXY", srv.URL)
+			t.Setenv("GOSUMDB", vkey+" "+srv.URL+"/unreachable")
+			t.Setenv("GONOSUMDB", "")
+			t.Setenv("GOPRIVATE", "")
+
+			vm := version.NewWithSettings(filepath.Join(t.TempDir(), "versions"), config.Settings{
+				HTTPTimeout: 5 * time.Second,
+				Downloader:  config.DefaultDownloader,
+				Source:      config.SourceGoProxy,
+			})
+			err := vm.InstallVersionWithOptions(v, version.InstallOptions{})
+			if tt.wantOK && err != nil {
+				t.Fatalf("install: %v", err)
+			}
+			if !tt.wantOK {
+				if err == nil {
+					t.Fatal("install succeeded with an unverified checksum")
+				}
+				if installed, _ := vm.IsVersionInstalled(v); installed {
+					t.Error("the unverified toolchain was installed")
+				}
+			}
+		})
+	}
+}
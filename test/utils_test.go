@@ -0,0 +1,603 @@
+package test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/philokun/gvm/internal/output"
+	"github.com/philokun/gvm/internal/utils"
+)
+
+func TestExtraHeaders(t *testing.T) {
+	t.Setenv("GVM_HTTP_HEADERS", "Authorization: Bearer secret; X-Team: infra\nbroken-entry")
+	h := utils.ExtraHeaders()
+	if got := h.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := h.Get("X-Team"); got != "infra" {
+		t.Errorf("X-Team = %q", got)
+	}
+	if len(h) != 2 {
+		t.Errorf("expected 2 headers, got %d", len(h))
+	}
+	if got := utils.RedactHeader("authorization", "Bearer secret"); got == "Bearer secret" {
+		t.Error("authorization header was not redacted")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	t.Setenv("GVM_USER_AGENT", "")
+	if utils.UserAgent() != utils.DefaultUserAgent {
+		t.Errorf("expected default user agent, got %q", utils.UserAgent())
+	}
+	t.Setenv("GVM_USER_AGENT", "corp-gvm/2")
+	if utils.UserAgent() != "corp-gvm/2" {
+		t.Errorf("expected overridden user agent, got %q", utils.UserAgent())
+	}
+}
+
+func TestDetectArchiveFormat(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00}, utils.FormatTarGz},
+		{"bzip2", []byte("BZh91AY&SY"), utils.FormatTarBz2},
+		{"zip", []byte("PK\x03\x04\x14\x00"), utils.FormatZip},
+		{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x00}, utils.FormatXz},
+		{"unknown", []byte("<html>"), ""},
+		{"short", []byte{0x1f}, ""},
+	}
+	for _, c := range cases {
+		path := filepath.Join(dir, "download?id="+c.name)
+		if err := os.WriteFile(path, c.header, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := utils.DetectArchiveFormat(path)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: DetectArchiveFormat = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAcquireLock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	lock, waited, err := utils.AcquireLock("go1.21.6", time.Second)
+	if err != nil || waited {
+		t.Fatalf("AcquireLock = %v, waited=%v", err, waited)
+	}
+	if _, _, err := utils.AcquireLock("go1.21.6", 100*time.Millisecond); err == nil {
+		t.Fatal("second AcquireLock should time out while the lock is held")
+	}
+	lock.Release()
+
+	// 未刷新的过期锁会被接管
+	lockPath := filepath.Join(home, ".gvm", "locks", "go1.21.6.lock")
+	if err := os.WriteFile(lockPath, []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * utils.LockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	lock, _, err = utils.AcquireLock("go1.21.6", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("stale lock was not taken over: %v", err)
+	}
+	lock.Release()
+}
+
+func TestExtractTarGzRestoresToolPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not preserved on Windows")
+	}
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "go.tar.gz")
+
+	// 模拟在 Windows 上重新打包的归档：所有条目都是 0644
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"go/bin/go", "go/pkg/tool/linux_amd64/compile", "go/VERSION"} {
+		body := []byte("x")
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	dest := filepath.Join(dir, "out")
+	// 父目录不在归档中，先创建
+	for _, d := range []string{"bin", "pkg/tool/linux_amd64"} {
+		if err := os.MkdirAll(filepath.Join(dest, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := utils.ExtractTarGz(archive, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]os.FileMode{
+		"bin/go":                       0755,
+		"pkg/tool/linux_amd64/compile": 0755,
+		"VERSION":                      0644,
+	} {
+		info, err := os.Stat(filepath.Join(dest, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 忽略 umask 去掉的位，只检查可执行位
+		if got := info.Mode().Perm(); got&0111 != want&0111 {
+			t.Errorf("%s: mode %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestDownloadReturnsStreamedSHA256(t *testing.T) {
+	content := bytes.Repeat([]byte("gvm"), 100000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	sum, err := utils.DownloadFileWithOptions(srv.URL+"/go.tar.gz", dest, utils.DownloadOptions{Resume: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sha256.Sum256(content)
+	if err := utils.MatchSHA256(sum, hex.EncodeToString(want[:])); err != nil {
+		t.Error(err)
+	}
+	if err := utils.VerifySHA256(dest, sum); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDownloadInPartsHashesInOrder(t *testing.T) {
+	content := make([]byte, 18<<20+123)
+	for i := range content {
+		content[i] = byte(i * 7 % 251)
+	}
+	want := sha256.Sum256(content)
+
+	var ranges int
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			mu.Lock()
+			ranges++
+			mu.Unlock()
+		}
+		if r.URL.Path == "/plain.tar.gz" {
+			// 不支持 Range 的服务器，应回退到顺序下载
+			w.Write(content)
+			return
+		}
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	for _, name := range []string{"go.tar.gz", "plain.tar.gz"} {
+		dest := filepath.Join(t.TempDir(), name)
+		sum, err := utils.DownloadFileWithOptions(srv.URL+"/"+name, dest, utils.DownloadOptions{Parts: 4})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := utils.MatchSHA256(sum, hex.EncodeToString(want[:])); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if err := utils.VerifySHA256(dest, sum); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	// 一次探测加 4 段，回退下载只有一次探测
+	if ranges != 6 {
+		t.Errorf("got %d range requests, want 6", ranges)
+	}
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+	}))
+	defer loop.Close()
+
+	client, err := utils.NewHTTPClient(5*time.Second, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(loop.URL + "/"); err == nil {
+		t.Error("expected redirect loop to be stopped")
+	}
+
+	// 127.0.0.1 不在允许列表中，第一次重定向即被拒绝
+	client, err = utils.NewHTTPClient(5*time.Second, "", []string{"go.dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(loop.URL + "/"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expected disallowed redirect error, got %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]os.FileMode{"0775": 0775, "664": 0664, "0o750": 0750} {
+		got, err := utils.ParseMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0", "0800", "1777", "rwx"} {
+		if _, err := utils.ParseMode(in); err == nil {
+			t.Errorf("ParseMode(%q) should fail", in)
+		}
+	}
+}
+
+func TestEnsureDirUsesConfiguredMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory modes are not supported on Windows")
+	}
+	t.Setenv("GVM_DIR_MODE", "0775")
+	dir := filepath.Join(t.TempDir(), "shared", "versions")
+	if err := utils.EnsureDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{dir, filepath.Dir(dir)} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != 0775 {
+			t.Errorf("%s: mode %v, want 0775", p, got)
+		}
+	}
+
+	t.Setenv("GVM_DIR_MODE", "0999")
+	if err := utils.EnsureDir(filepath.Join(dir, "new")); err == nil {
+		t.Error("expected invalid GVM_DIR_MODE to be rejected")
+	}
+}
+
+func TestExtractArchiveExcludesSrc(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "go.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"go/", "go/src/", "go/src/fmt/", "go/src/fmt/print.go", "go/srcfile", "go/VERSION"} {
+		hdr := &tar.Header{Name: name, Mode: 0755, Typeflag: tar.TypeDir}
+		if !strings.HasSuffix(name, "/") {
+			hdr = &tar.Header{Name: name, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			tw.Write([]byte("x"))
+		}
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	dest := filepath.Join(dir, "out")
+	if err := utils.ExtractArchiveWithOptions(archive, "go.tar.gz", dest, utils.ExtractOptions{Exclude: []string{"src"}}); err != nil {
+		t.Fatal(err)
+	}
+	if utils.FileExists(filepath.Join(dest, "src")) {
+		t.Error("src/ should not have been extracted")
+	}
+	for _, name := range []string{"srcfile", "VERSION"} {
+		if !utils.FileExists(filepath.Join(dest, name)) {
+			t.Errorf("%s should have been extracted", name)
+		}
+	}
+}
+
+func TestExtractRejectsPathTraversal(t *testing.T) {
+	for _, entry := range []string{"../evil", "go/../../evil"} {
+		dir := t.TempDir()
+
+		var tarBuf bytes.Buffer
+		gz := gzip.NewWriter(&tarBuf)
+		tw := tar.NewWriter(gz)
+		if err := tw.WriteHeader(&tar.Header{Name: entry, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte("x"))
+		tw.Close()
+		gz.Close()
+
+		var zipBuf bytes.Buffer
+		zw := zip.NewWriter(&zipBuf)
+		w, err := zw.Create(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("x"))
+		zw.Close()
+
+		for name, data := range map[string][]byte{"go.tar.gz": tarBuf.Bytes(), "go.zip": zipBuf.Bytes()} {
+			archive := filepath.Join(dir, name)
+			if err := os.WriteFile(archive, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			dest := filepath.Join(dir, "out", "versions", "go1")
+			err := utils.ExtractArchive(archive, name, dest)
+			if err == nil || !strings.Contains(err.Error(), "outside") {
+				t.Errorf("%s with entry %q: err = %v, want the entry to be rejected", name, entry, err)
+			}
+			for _, escaped := range []string{filepath.Join(dir, "out", "versions", "evil"), filepath.Join(dir, "out", "evil")} {
+				if utils.FileExists(escaped) {
+					t.Errorf("%s with entry %q wrote %s", name, entry, escaped)
+				}
+			}
+		}
+	}
+}
+
+func TestExtractTruncatedArchiveIsCorrupt(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	data := bytes.Repeat([]byte("go"), 64*1024)
+	tw.WriteHeader(&tar.Header{Name: "go/bin/", Mode: 0755, Typeflag: tar.TypeDir})
+	tw.WriteHeader(&tar.Header{Name: "go/bin/go", Mode: 0755, Size: int64(len(data)), Typeflag: tar.TypeReg})
+	tw.Write(data)
+	tw.Close()
+	gz.Close()
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "go.tar.gz")
+	if err := os.WriteFile(archive, buf.Bytes()[:buf.Len()/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := utils.ExtractArchive(archive, "go.tar.gz", filepath.Join(dir, "out"))
+	if !utils.IsCorruptArchive(err) {
+		t.Fatalf("expected a corrupt archive error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), archive) || !strings.Contains(err.Error(), fmt.Sprintf("%d bytes", buf.Len()/2)) {
+		t.Errorf("error should name the archive and its size: %v", err)
+	}
+
+	// 不是 gzip 数据时同样视为损坏
+	if err := os.WriteFile(archive, []byte("\x1f\x8bnot gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := utils.ExtractArchive(archive, "go.tar.gz", filepath.Join(dir, "out2")); !utils.IsCorruptArchive(err) {
+		t.Errorf("expected a corrupt archive error for a bad header, got %v", err)
+	}
+}
+
+func TestEnsureShellPathFish(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	utils.SetShellOverride("fish")
+	t.Cleanup(func() { utils.SetShellOverride("") })
+
+	// 旧版本写入 config.fish 的 bash 语法代码块
+	fishDir := filepath.Join(home, ".config", "fish")
+	if err := os.MkdirAll(fishDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "set -gx EDITOR vim\n\n# >>> gvm >>>\nexport PATH=\"/old/shims:$PATH\"\n# <<< gvm <<<\n"
+	if err := os.WriteFile(filepath.Join(fishDir, "config.fish"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := filepath.Join(home, ".gvm", "shims")
+	changed, err := utils.EnsureShellPath(bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(fishDir, "conf.d", "gvm.fish")
+	if changed != want {
+		t.Errorf("EnsureShellPath wrote %q, want %q", changed, want)
+	}
+	data, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf("set -gx PATH %q $PATH", bin)) || strings.Contains(string(data), "export") {
+		t.Errorf("gvm.fish should use fish syntax:\n%s", data)
+	}
+	config, _ := os.ReadFile(filepath.Join(fishDir, "config.fish"))
+	if string(config) != "set -gx EDITOR vim\n" {
+		t.Errorf("the legacy block should be removed from config.fish, got:\n%s", config)
+	}
+
+	// 再次调用时内容不变，不改写文件
+	if changed, err := utils.EnsureShellPath(bin); err != nil || changed != "" {
+		t.Errorf("second EnsureShellPath = %q, %v; want no change", changed, err)
+	}
+}
+
+func TestPathWithinOS(t *testing.T) {
+	tests := []struct {
+		path, root, goos string
+		want             bool
+	}{
+		{`C:\Users\me\.gvm\versions\go1.21.5`, `c:/users/me/.gvm/versions`, "windows", true},
+		{`c:/users/ME/.gvm/versions/go1.21.5/`, `C:\Users\me\.gvm\versions\`, "windows", true},
+		{`C:\Users\me\.gvm\versions`, `c:\users\me\.gvm\versions`, "windows", true},
+		{`C:\Users\me\.gvm\versions2\go1.21.5`, `C:\Users\me\.gvm\versions`, "windows", false},
+		{`D:\Users\me\.gvm\versions\go1.21.5`, `C:\Users\me\.gvm\versions`, "windows", false},
+		{`C:\go`, `C:\`, "windows", true},
+		{"/home/me/.gvm/versions/go1.21.5", "/home/me/.gvm/versions/", "linux", true},
+		{"/home/me/.gvm/versions2/go1.21.5", "/home/me/.gvm/versions", "linux", false},
+		{"/Home/me/.gvm/versions/go1.21.5", "/home/me/.gvm/versions", "linux", false},
+		{"/opt/go/home/me/.gvm/versions/go", "/home/me/.gvm/versions", "linux", false},
+	}
+	for _, tt := range tests {
+		if got := utils.PathWithinOS(tt.path, tt.root, tt.goos); got != tt.want {
+			t.Errorf("PathWithinOS(%q, %q, %s) = %v, want %v", tt.path, tt.root, tt.goos, got, tt.want)
+		}
+	}
+}
+
+func TestDetectShellOverride(t *testing.T) {
+	utils.SetShellOverride("/usr/local/bin/-Zsh.exe")
+	t.Cleanup(func() { utils.SetShellOverride("") })
+	if name, source := utils.DetectShell(); name != "zsh" || source != utils.ShellFromFlag {
+		t.Errorf("DetectShell() = %q, %q; want zsh from %s", name, source, utils.ShellFromFlag)
+	}
+}
+
+func TestZipHash1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "m.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	files := map[string]string{"m@v1/b.txt": "b", "m@v1/a.txt": "a"}
+	for _, name := range []string{"m@v1/b.txt", "m@v1/a.txt"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(files[name]))
+	}
+	zw.Close()
+	f.Close()
+
+	// go.sum 的 h1: 摘要：按文件名排序的 "<sha256>  <name>" 行再做一次 SHA256
+	summary := sha256.New()
+	for _, name := range []string{"m@v1/a.txt", "m@v1/b.txt"} {
+		fmt.Fprintf(summary, "%x  %s\n", sha256.Sum256([]byte(files[name])), name)
+	}
+	want := "h1:" + base64.StdEncoding.EncodeToString(summary.Sum(nil))
+
+	got, err := utils.ZipHash1(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("ZipHash1 = %s, want %s", got, want)
+	}
+}
+
+func TestExternalDownloaderRedirectHosts(t *testing.T) {
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl is not installed")
+	}
+	defer output.SetProgress(output.Progress())
+	output.SetProgress(false)
+	content := []byte("go archive")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/go.tar.gz", http.StatusFound)
+			return
+		}
+		http.ServeContent(w, r, "go.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	// 重定向到允许的主机：curl 下载内置客户端解析出的最终地址
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	opts := utils.DownloadOptions{Downloader: utils.DownloaderCurl, RedirectHosts: []string{"127.0.0.1"}}
+	if _, err := utils.DownloadFileWithOptions(srv.URL+"/start", dest, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, content) {
+		t.Errorf("downloaded %q, want %q", got, content)
+	}
+
+	// 127.0.0.1 不在允许列表中，curl 不会被调用
+	dest = filepath.Join(t.TempDir(), "go.tar.gz")
+	opts.RedirectHosts = []string{"go.dev"}
+	if _, err := utils.DownloadFileWithOptions(srv.URL+"/start", dest, opts); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expected disallowed redirect error, got %v", err)
+	}
+	if utils.FileExists(dest) {
+		t.Error("the disallowed download was written")
+	}
+}
+
+func TestExternalDownloaderKeepsHeadersOffCommandLine(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake curl is a shell script")
+	}
+	defer output.SetProgress(output.Progress())
+	output.SetProgress(false)
+	t.Setenv("GVM_HTTP_HEADERS", "Authorization: Bearer secret")
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	// 假的 curl 记录命令行与 -H @file 指向的文件内容，并写出下载结果
+	bin := t.TempDir()
+	args, headers := filepath.Join(tmp, "args"), filepath.Join(tmp, "headers")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" > %s
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --header) cat "${2#@}" > %s; ls -l "${2#@}" >> %s; shift ;;
+    --output) echo archive > "$2"; shift ;;
+  esac
+  shift
+done
+`, args, headers, headers)
+	if err := os.WriteFile(filepath.Join(bin, "curl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// 请求头只发给镜像：gvm 先用内置客户端解析最终地址，再交给 curl
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	defer utils.SetHeaderHosts(nil)
+	utils.SetHeaderHosts([]string{strings.TrimPrefix(srv.URL, "http://")})
+
+	dest := filepath.Join(t.TempDir(), "go.tar.gz")
+	if _, err := utils.DownloadFileWithOptions(srv.URL+"/go.tar.gz", dest, utils.DownloadOptions{Downloader: utils.DownloaderCurl}); err != nil {
+		t.Fatal(err)
+	}
+	cmdline, _ := os.ReadFile(args)
+	if strings.Contains(string(cmdline), "secret") {
+		t.Errorf("the header value is on the command line: %s", cmdline)
+	}
+	got, _ := os.ReadFile(headers)
+	if !strings.Contains(string(got), "Authorization: Bearer secret") || !strings.Contains(string(got), "-rw-------") {
+		t.Errorf("header file = %q, want the header in a 0600 file", got)
+	}
+	if leftover, _ := filepath.Glob(filepath.Join(tmp, "gvm-headers-*")); len(leftover) != 0 {
+		t.Errorf("header files left behind: %v", leftover)
+	}
+}
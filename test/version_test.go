@@ -1,8 +1,17 @@
 package test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/philokun/gvm/internal/version"
 )
 
 
@@ -10,4 +19,426 @@ import (
 func TestVersionManager(t *testing.T) {
 	homeDir, _ := os.UserHomeDir()
 	t.Logf("Home directory: %s", homeDir)
-}
\ No newline at end of file
+}
+
+func TestSeries(t *testing.T) {
+	cases := map[string]string{
+		"go1.21.5":  "go1.21",
+		"go1.22rc1": "go1.22",
+		"1.20":      "go1.20",
+		"go1.9.2":   "go1.9",
+	}
+	for in, want := range cases {
+		if got := version.Series(in); got != want {
+			t.Errorf("Series(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		content string
+		want    string
+	}{
+		{"module example.com/m\n\ngo 1.20\n", "go1.20"},
+		{"module example.com/m\n\ngo 1.22\n", "go1.22.0"},
+		{"module example.com/m\n\ngo 1.21.3\n\ntoolchain go1.22.5 // pinned\n", "go1.22.5"},
+	}
+	for i, c := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("go%d.mod", i))
+		if err := os.WriteFile(path, []byte(c.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := version.ParseGoMod(path)
+		if err != nil {
+			t.Fatalf("ParseGoMod: %v", err)
+		}
+		if got != c.want {
+			t.Errorf("ParseGoMod(%q) = %q, want %q", c.content, got, c.want)
+		}
+	}
+}
+
+func TestParseVersionFile(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		content string
+		want    string
+		series  bool
+	}{
+		{"1.21.5\n", "go1.21.5", false},
+		{"go1.21.5", "go1.21.5", false},
+		{"v1.21.5\n", "go1.21.5", false},
+		{"  1.22rc1  \n", "go1.22rc1", false},
+		{"1.21\n", "go1.21", true},
+		{"go1.20\n", "go1.20", true},
+		{"# pinned for CI\n\n   \n1.22.3\n1.21.0\n", "go1.22.3", false},
+	}
+	for i, c := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("version%d", i))
+		if err := os.WriteFile(path, []byte(c.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, series, err := version.ParseVersionFile(path)
+		if err != nil {
+			t.Fatalf("ParseVersionFile(%q): %v", c.content, err)
+		}
+		if got != c.want || series != c.series {
+			t.Errorf("ParseVersionFile(%q) = %q, %v, want %q, %v", c.content, got, series, c.want, c.series)
+		}
+	}
+
+	for i, content := range []string{"", "# only a comment\n", "1\n", "latest\n", "1.21.x\n"} {
+		path := filepath.Join(dir, fmt.Sprintf("invalid%d", i))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if v, _, err := version.ParseVersionFile(path); err == nil {
+			t.Errorf("ParseVersionFile(%q) = %q, want an error", content, v)
+		}
+	}
+}
+
+func TestResolveVersionFileSeries(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, v := range []string{"go1.21.2", "go1.21.10", "go1.21.9", "go1.22.0"} {
+		if err := os.MkdirAll(filepath.Join(home, ".gvm", "versions", v), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(home, version.VersionFileName)
+	if err := os.WriteFile(path, []byte("1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := version.New().ResolveVersionFile(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "go1.21.10" {
+		t.Errorf("ResolveVersionFile(1.21) = %q, want the latest installed patch go1.21.10", got)
+	}
+}
+
+func TestFindVersionFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := version.FindVersionFile(nested); err == nil {
+		t.Error("FindVersionFile found a file in a tree without .go-version")
+	}
+
+	want := filepath.Join(root, version.VersionFileName)
+	if err := os.WriteFile(want, []byte("1.21.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := version.FindVersionFile(nested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("FindVersionFile() = %q, want %q from the parent directory", got, want)
+	}
+}
+
+func TestVersionsCacheRecoversFromCorruption(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	cacheFile := filepath.Join(home, ".gvm", "cache", "versions.json")
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟写入中断留下的截断文件
+	if err := os.WriteFile(cacheFile, []byte(`[{"version":"go1.22.0","stable":tr`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := version.LoadVersionsCache(time.Hour); ok {
+		t.Fatal("expected corrupted cache to be treated as a miss")
+	}
+	if _, err := os.Stat(cacheFile); !os.IsNotExist(err) {
+		t.Fatal("expected corrupted cache file to be removed")
+	}
+
+	want := []version.GoVersion{{Version: "go1.22.0", Stable: true}}
+	if err := version.SaveVersionsCache(want); err != nil {
+		t.Fatal(err)
+	}
+	got, _, ok := version.LoadVersionsCache(time.Hour)
+	if !ok || len(got) != 1 || got[0].Version != "go1.22.0" {
+		t.Fatalf("unexpected cache contents after recovery: %+v (ok=%v)", got, ok)
+	}
+}
+
+func TestInstallPhaseErrors(t *testing.T) {
+	err := fmt.Errorf("install: %w", &version.Error{
+		Code:  version.CodeDownloadFailed,
+		Phase: version.ErrDownload,
+		Err:   fmt.Errorf("failed to download go1.22.0.linux-amd64.tar.gz from all mirrors"),
+	})
+	if !errors.Is(err, version.ErrDownload) {
+		t.Error("expected errors.Is(err, ErrDownload)")
+	}
+	if errors.Is(err, version.ErrExtract) {
+		t.Error("download error must not match ErrExtract")
+	}
+	if code := version.ErrorCode(err); code != version.CodeDownloadFailed {
+		t.Errorf("ErrorCode = %q", code)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"go1.9.2", "go1.10", -1},
+		{"go1.21.10", "go1.21.9", 1},
+		{"go1.22rc1", "go1.22.0", -1},
+		{"go1.22beta1", "go1.22rc1", -1},
+		{"go1.21rc1", "go1.21", -1},
+		{"go1.9", "go1.21", -1},
+		{"go1.20", "go1.20.0", 0},
+		{"1.21.5", "go1.21.5", 0},
+	}
+	for _, c := range cases {
+		if got := version.CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestValidateMirrorTemplate(t *testing.T) {
+	valid := []string{
+		"https://github.com/example/go/releases/download/{goversion}/go-{version}-{os}-{arch}.{ext}",
+		"http://mirror.local/{filename}",
+	}
+	for _, tpl := range valid {
+		if err := version.ValidateMirrorTemplate(tpl); err != nil {
+			t.Errorf("ValidateMirrorTemplate(%q) = %v", tpl, err)
+		}
+	}
+	invalid := []string{
+		"ftp://mirror.local/{filename}",
+		"https://mirror.local/go-{os}-{arch}.{ext}",
+	}
+	for _, tpl := range invalid {
+		if err := version.ValidateMirrorTemplate(tpl); err == nil {
+			t.Errorf("ValidateMirrorTemplate(%q) should fail", tpl)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"1024": 1024,
+		"500M": 500 << 20,
+		"2GB":  2 << 30,
+		"4GiB": 4 << 30,
+		"1.5g": 3 << 29,
+		"0":    0,
+	}
+	for in, want := range cases {
+		got, err := version.ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	if _, err := version.ParseSize("lots"); err == nil {
+		t.Error("ParseSize(\"lots\") should fail")
+	}
+}
+
+func TestQuarantineArchive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	src := filepath.Join(t.TempDir(), "go1.21.0.linux-amd64.tar.gz")
+	if err := os.WriteFile(src, []byte("not a toolchain"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest, err := version.QuarantineArchive(src, version.QuarantineRecord{
+		File:     "go1.21.0.linux-amd64.tar.gz",
+		URL:      "https://example.com/go1.21.0.linux-amd64.tar.gz",
+		Expected: "aaaa",
+		Actual:   "bbbb",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source file should have been moved")
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "not a toolchain" {
+		t.Errorf("quarantined file: %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(dest + ".json"); err != nil || !strings.Contains(string(data), "https://example.com/") {
+		t.Errorf("quarantine record: %s, %v", data, err)
+	}
+}
+
+func TestArchiveForSkipsInstallers(t *testing.T) {
+	var v version.GoVersion
+	data := `{"version":"go1.21.0","stable":true,"files":[
+		{"filename":"go1.21.0.windows-amd64.msi","os":"windows","arch":"amd64","kind":"installer"},
+		{"filename":"go1.21.0.windows-amd64.zip","os":"windows","arch":"amd64","kind":"archive"},
+		{"filename":"go1.21.0.src.tar.gz","os":"","arch":"","kind":"source"}]}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	f := v.ArchiveFor("windows", "amd64")
+	if f == nil || f.Filename != "go1.21.0.windows-amd64.zip" {
+		t.Errorf("ArchiveFor = %+v, want the zip archive", f)
+	}
+	if f := v.ArchiveFor("linux", "amd64"); f != nil {
+		t.Errorf("ArchiveFor(linux) = %+v, want nil", f)
+	}
+}
+
+func TestSelectArchiveIsDeterministic(t *testing.T) {
+	files := []version.GoFile{
+		{Filename: "go1.21.0.darwin-arm64.zip", OS: "darwin", Arch: "arm64", Kind: "archive"},
+		{Filename: "go1.21.0.darwin-arm64.pkg", OS: "darwin", Arch: "arm64", Kind: "installer"},
+		{Filename: "go1.21.0.darwin-arm64-universal.tar.gz", OS: "darwin", Arch: "arm64", Kind: "archive"},
+		{Filename: "go1.21.0.darwin-arm64.tar.gz", OS: "darwin", Arch: "arm64", Kind: "archive"},
+	}
+	tests := []struct {
+		prefer []string
+		want   string
+	}{
+		{nil, "go1.21.0.darwin-arm64-universal.tar.gz"},
+		{[]string{"universal"}, "go1.21.0.darwin-arm64-universal.tar.gz"},
+		{[]string{".zip"}, "go1.21.0.darwin-arm64.zip"},
+	}
+	for _, tt := range tests {
+		// 结果不应依赖 JSON 中的文件顺序
+		for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}} {
+			v := version.GoVersion{Version: "go1.21.0"}
+			for _, i := range order {
+				v.Files = append(v.Files, files[i])
+			}
+			f, candidates := v.SelectArchive("darwin", "arm64", tt.prefer)
+			if f == nil || f.Filename != tt.want {
+				t.Errorf("SelectArchive(prefer=%v, order=%v) = %+v, want %s", tt.prefer, order, f, tt.want)
+			}
+			if len(candidates) != 3 {
+				t.Errorf("got %d candidates, want 3 archives", len(candidates))
+			}
+		}
+	}
+}
+
+func TestReadHistory(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	entries, err := version.ReadHistory()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadHistory without a log = %v, %v", entries, err)
+	}
+
+	log := "2024-01-02T03:04:05Z\t-\tgo1.21.0\tmanual\t\n" +
+		"garbage\n" +
+		"2024-01-03T03:04:05Z\tgo1.21.0\tgo1.22.0\talias\tstable\n"
+	if err := os.MkdirAll(filepath.Join(home, ".gvm"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".gvm", "history.log"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = version.ReadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.From != "" || e.To != "go1.21.0" || e.Source != version.SwitchManual {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := entries[1]; e.From != "go1.21.0" || e.Source != version.SwitchAlias || e.Detail != "stable" {
+		t.Errorf("second entry = %+v", e)
+	}
+}
+
+func TestParseSemVer(t *testing.T) {
+	tests := []struct {
+		in   string
+		want version.SemVer
+	}{
+		{"go1.21.5", version.SemVer{Version: "go1.21.5", Major: 1, Minor: 21, Patch: 5, Series: "go1.21"}},
+		{"1.22rc1", version.SemVer{Version: "go1.22rc1", Major: 1, Minor: 22, Prerelease: "rc1", Series: "go1.22"}},
+		{"go1.21beta2", version.SemVer{Version: "go1.21beta2", Major: 1, Minor: 21, Prerelease: "beta2", Series: "go1.21"}},
+	}
+	for _, tt := range tests {
+		got, err := version.ParseSemVer(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSemVer(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "latest", "go1.21.x", "1.21-rc1"} {
+		if _, err := version.ParseSemVer(in); err == nil {
+			t.Errorf("ParseSemVer(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestManagedVersion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	vm := version.New()
+	versions := filepath.Join(home, ".gvm", "versions")
+
+	tests := []struct {
+		root string
+		want string
+		ok   bool
+	}{
+		{filepath.Join(versions, "go1.21.5"), "go1.21.5", true},
+		{filepath.Join(versions, "go1.21.5") + string(filepath.Separator), "go1.21.5", true},
+		{filepath.Join(versions, ".", "go1.21.5"), "go1.21.5", true},
+		{filepath.Join(versions, "go1.21.5", "nested"), "", false},
+		{versions + "2" + string(filepath.Separator) + "go1.21.5", "", false},
+		{filepath.Join(home, "opt", ".gvm", "versions", "go1.21.5"), "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := vm.ManagedVersion(tt.root)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ManagedVersion(%q) = %q, %v; want %q, %v", tt.root, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCheckVersionMultiLineVersionFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the go binary")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// go1.21.5 目录中放的是 go1.21.6，VERSION 不一致时仍应报错
+	for _, dir := range []string{"go1.21.6", "go1.21.5"} {
+		root := filepath.Join(home, ".gvm", "versions", dir)
+		if err := os.MkdirAll(filepath.Join(root, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		// Go 1.21 起的 VERSION 格式：版本号之后是构建时间
+		if err := os.WriteFile(filepath.Join(root, "VERSION"), []byte("go1.21.6\ntime 2024-01-05T18:21:45Z\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		script := "#!/bin/sh\necho go version go1.21.6 linux/amd64\n"
+		if err := os.WriteFile(filepath.Join(root, "bin", "go"), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := version.New().CheckVersion("go1.21.6"); err != nil {
+		t.Errorf("CheckVersion with a two-line VERSION: %v", err)
+	}
+	if err := version.New().CheckVersion("go1.21.5"); err == nil {
+		t.Error("CheckVersion(go1.21.5) succeeded against a go1.21.6 VERSION file")
+	}
+}